@@ -20,6 +20,24 @@
 //	pixcel convert icon.gif -W 100 -H 50 -o stretched.html
 //	pixcel convert icon.gif --no-html
 //	pixcel convert art.png -W 600 -H 306 -o art.html --smooth-load
+//	pixcel convert avatar.png -W 64 -H 64 --fit pad --gravity north -o avatar.html
+//	pixcel convert sprite.png -W 32 --cell-size 2x1 -o c64.html
+//	pixcel convert sheet.png --crop 32,0,16,16 -W 16 -o sprite.html
+//	pixcel convert sticker.webp -W 64 -o sticker.html
+//
+// PNG, JPEG, GIF, BMP, TIFF and WebP inputs are supported; animated GIF, APNG
+// and WebP files become a pure CSS animation.
+//
+// Slice a spritesheet and convert each tile:
+//
+//	pixcel sprites sheet.png --grid 16x16 -o sprites.html
+//	pixcel sprites sheet.png --mode files --out-dir tiles
+//	pixcel sprites walk.png --grid 32x32 --mode animate --delay 120ms
+//
+// Assemble frame images into an animation:
+//
+//	pixcel animate frame*.png --fps 12 -o anim.html
+//	pixcel animate frames/ --frame-delay 0=500ms --ping-pong --loop 3
 //
 // # Flags
 //
@@ -30,8 +48,54 @@
 //   - --no-html         output only the <table>, omit the HTML wrapper
 //   - --smooth-load     hide content until fully loaded to prevent progressive rendering
 //   - --scaler          scaling algorithm: nearest, catmullrom, bilinear, approxbilinear (default: nearest)
+//   - --crop            convert only the region x,y,w,h of the source image (default: whole image)
+//   - --fit             fit mode when both width and height are set: stretch, contain, cover, pad (default: stretch)
+//   - --gravity         anchor for --fit cover/pad: center, north, south, east, west, northeast, ... (default: center)
+//   - --pad-color       padding colour for --fit pad: transparent or hex (default: transparent)
+//   - --cell-size       CSS pixel size of each cell as WxH or N (default: 1x1)
+//   - --max-frames      maximum number of animation frames to keep (default: 10)
+//   - --sampling        how excess frames are dropped: uniform, duration, distinct, fps (default: uniform)
+//   - --sample-fps      frame rate for --sampling fps (default: 10)
+//   - --loop            number of plays for animations, 0 loops forever (default: from the source)
+//   - --player          add play/pause, step, speed and frame slider controls to animations
+//   - --reduced-motion  poster frame shown for prefers-reduced-motion: first, last or an index (default: off)
+//   - --web-component   output a self-contained <pixcel-art> custom element using Shadow DOM
+//   - --alt             text alternative announced by screen readers (marks the art as an image)
+//   - --description     longer description shown as a caption below the art (requires --alt)
+//   - --minify          minify the HTML: drop whitespace, optional quotes and end tags, compact CSS
+//   - --profile         output profile: web, email (table attributes only, no CSS or scripts) (default: web)
+//   - --max-bytes       shrink the size, frame count and cell size until the HTML fits in this many bytes (default: no limit)
+//   - --compress        also write pre-compressed copies of the output: gzip (.gz), br (.br), or both as gzip,br
+//   - --responsive      scale the art to the width of its container, keeping its aspect ratio
+//   - --min-width       minimum rendered width in CSS pixels for --responsive (default: none)
+//   - --max-width       maximum rendered width in CSS pixels for --responsive (default: none)
+//   - --class-prefix    prefix of CSS class and @keyframes names (default: pixcel)
+//   - --unique-prefix   append a random suffix to --class-prefix for every output
+//   - --template        custom page template file defining "image" and/or "animation" templates
+//   - --theme           page styling preset: dark, light, transparent (default: dark)
+//   - --background      page background CSS colour (default: from --theme)
+//   - --surface         background CSS colour of the card holding the art (default: from --theme)
+//   - --text-color      CSS colour of labels and player controls (default: from --theme)
+//   - --padding         card padding in CSS pixels (default: from --theme)
+//   - --radius          card corner radius in CSS pixels (default: from --theme)
+//   - --shadow          card CSS box-shadow, e.g. none (default: from --theme)
+//   - --align           card position: center, top-left (default: from --theme)
+//   - --frame-delta     render later animation frames as only the pixels that changed from the first
 //   - --obfuscate       randomize inline CSS styling for CAPTCHA/scraping protection (browser only)
 //
+// The sprites command also accepts:
+//
+//   - --grid            tile size as WxH or N, or auto to split on transparent gutters (default: auto)
+//   - --mode            page, files or animate (default: page)
+//   - --delay           time each sprite is shown in animate mode (default: 100ms)
+//   - --out-dir         output directory for files mode (default: .)
+//
+// The animate command also accepts:
+//
+//   - --fps             frames per second (default: 10)
+//   - --frame-delay     per-frame duration override as INDEX=DURATION, repeatable
+//   - --ping-pong       play the frames forwards then backwards
+//
 // # SDK Usage
 //
 // The underlying SDK can also be imported directly:
@@ -0,0 +1,195 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/H0llyW00dzZ/pixcel/src/pixcel"
+	"github.com/spf13/cobra"
+)
+
+// animateCmd flag values, scoped to this file.
+var (
+	flagAnimateOutput     string
+	flagAnimateFPS        float64
+	flagAnimateFrameDelay []string
+	flagAnimatePingPong   bool
+)
+
+// animateCmd assembles individual frame images into a CSS animation.
+var animateCmd = &cobra.Command{
+	Use:   "animate <frame|dir>...",
+	Short: renderTemplate("animate.short"),
+	Long:  renderTemplate("animate.long"),
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runAnimate,
+}
+
+func init() {
+	animateCmd.Flags().StringVarP(&flagAnimateOutput, "output", "o", "go_pixel_anim.html", "output HTML file path")
+	animateCmd.Flags().Float64Var(&flagAnimateFPS, "fps", 10, "frames per second")
+	animateCmd.Flags().StringArrayVar(&flagAnimateFrameDelay, "frame-delay", nil, "per-frame duration override as INDEX=DURATION (e.g. 0=500ms), repeatable")
+	animateCmd.Flags().BoolVar(&flagAnimatePingPong, "ping-pong", false, "play the frames forwards then backwards")
+	addConverterFlags(animateCmd)
+
+	rootCmd.AddCommand(animateCmd)
+}
+
+// runAnimate is the RunE handler for the animate subcommand.
+func runAnimate(_ *cobra.Command, args []string) error {
+	if flagAnimateFPS <= 0 {
+		return fmt.Errorf("invalid --fps %v (must be positive)", flagAnimateFPS)
+	}
+
+	paths, err := framePaths(args)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := parseFrameDelays(flagAnimateFrameDelay, len(paths))
+	if err != nil {
+		return err
+	}
+
+	opts, err := converterOptions()
+	if err != nil {
+		return err
+	}
+	converter := pixcel.New(opts...)
+
+	anim := &pixcel.Animation{} // loops forever unless --loop is set
+	base := time.Duration(float64(time.Second) / flagAnimateFPS)
+	for i, path := range paths {
+		img, _, err := loadImage(path)
+		if err != nil {
+			return fmt.Errorf("frame %d (%s): %w", i, path, err)
+		}
+		delay := base
+		if d, ok := overrides[i]; ok {
+			delay = d
+		}
+		anim.Frames = append(anim.Frames, img)
+		anim.Delays = append(anim.Delays, delay)
+	}
+	if flagAnimatePingPong {
+		pingPong(anim)
+	}
+	fmt.Printf("Loaded %d frames\n", len(anim.Frames))
+
+	outFile, err := createOutput(flagAnimateOutput)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	if err := converter.ConvertAnimation(context.Background(), anim, outFile); err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+	if err := outFile.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Done! Saved animated HTML pixel art to %s\n", flagAnimateOutput)
+	outFile.printSizes()
+	return nil
+}
+
+// framePaths expands the arguments into an ordered list of frame files. A
+// directory contributes its image files in lexical order, and a glob pattern
+// that the shell left unexpanded is expanded here.
+func framePaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		switch {
+		case err == nil && info.IsDir():
+			files, err := dirFrames(arg)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, files...)
+		case err != nil && strings.ContainsAny(arg, "*?["):
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+			}
+			paths = append(paths, matches...)
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no frame images found")
+	}
+	return paths, nil
+}
+
+// dirFrames returns the image files in dir, sorted by name.
+func dirFrames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !isImageExt(e.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// isImageExt reports whether name has the extension of a decodable format.
+func isImageExt(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".tif", ".tiff", ".webp":
+		return true
+	}
+	return false
+}
+
+// parseFrameDelays parses INDEX=DURATION overrides for n frames.
+func parseFrameDelays(specs []string, n int) (map[int]time.Duration, error) {
+	overrides := make(map[int]time.Duration, len(specs))
+	for _, spec := range specs {
+		idx, dur, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --frame-delay %q (expected INDEX=DURATION)", spec)
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(idx))
+		if err != nil || i < 0 || i >= n {
+			return nil, fmt.Errorf("invalid --frame-delay %q: frame index must be between 0 and %d", spec, n-1)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(dur))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid --frame-delay %q: duration must be positive", spec)
+		}
+		overrides[i] = d
+	}
+	return overrides, nil
+}
+
+// pingPong appends the inner frames in reverse order, so the animation plays
+// forwards then backwards without repeating the first and last frames.
+func pingPong(a *pixcel.Animation) {
+	n := len(a.Frames)
+	for i := n - 2; i > 0; i-- {
+		a.Frames = append(a.Frames, a.Frames[i])
+		a.Delays = append(a.Delays, a.Delays[i])
+	}
+}
@@ -6,17 +6,30 @@
 package cli
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
 	"image"
 	"image/color"
 	"image/gif"
 	"image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/H0llyW00dzZ/pixcel/src/pixcel"
+	"github.com/andybalholm/brotli"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
 )
 
 // createTestPNG writes a minimal 4x4 red PNG to the given path.
@@ -394,3 +407,1200 @@ func TestRunConvert_WithScaler(t *testing.T) {
 	// Reset
 	flagScaler = "nearest"
 }
+
+// --- Fit CLI tests ---
+
+func TestParseFit(t *testing.T) {
+	assert.Equal(t, pixcel.FitStretch, parseFit("stretch"))
+	assert.Equal(t, pixcel.FitContain, parseFit("contain"))
+	assert.Equal(t, pixcel.FitCover, parseFit("COVER"))
+	assert.Equal(t, pixcel.FitPad, parseFit("pad"))
+	assert.Equal(t, pixcel.FitStretch, parseFit("unknown"), "default fallback")
+}
+
+func TestParseGravity(t *testing.T) {
+	assert.Equal(t, pixcel.GravityCenter, parseGravity("center"))
+	assert.Equal(t, pixcel.GravityNorth, parseGravity("north"))
+	assert.Equal(t, pixcel.GravitySouthWest, parseGravity("SouthWest"))
+	assert.Equal(t, pixcel.GravityCenter, parseGravity("unknown"), "default fallback")
+}
+
+func TestParseSampling(t *testing.T) {
+	assert.Equal(t, pixcel.SampleUniform, parseSampling("uniform"))
+	assert.Equal(t, pixcel.SampleDuration, parseSampling("duration"))
+	assert.Equal(t, pixcel.SampleDistinct, parseSampling("Distinct"))
+	assert.Equal(t, pixcel.SampleFPS, parseSampling("fps"))
+	assert.Equal(t, pixcel.SampleUniform, parseSampling("unknown"), "default fallback")
+}
+
+func TestParsePoster(t *testing.T) {
+	tests := []struct {
+		input   string
+		poster  int
+		enabled bool
+	}{
+		{"", pixcel.PosterFirst, false},
+		{"first", pixcel.PosterFirst, true},
+		{"LAST", pixcel.PosterLast, true},
+		{" 3 ", 3, true},
+	}
+	for _, tt := range tests {
+		poster, enabled, err := parsePoster(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.poster, poster, tt.input)
+		assert.Equal(t, tt.enabled, enabled, tt.input)
+	}
+
+	for _, bad := range []string{"middle", "-1", "1.5"} {
+		_, _, err := parsePoster(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected color.Color
+	}{
+		{"transparent", color.Transparent},
+		{"", color.Transparent},
+		{"#ff0000", color.NRGBA{R: 255, A: 255}},
+		{"00ff00", color.NRGBA{G: 255, A: 255}},
+		{"#00f", color.NRGBA{B: 255, A: 255}},
+		{"#11223380", color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0x80}},
+	}
+	for _, tt := range tests {
+		got, err := parseColor(tt.input)
+		require.NoError(t, err, "parseColor(%q)", tt.input)
+		assert.Equal(t, tt.expected, got, "parseColor(%q)", tt.input)
+	}
+
+	for _, bad := range []string{"red", "#12", "#gggggg"} {
+		_, err := parseColor(bad)
+		assert.Error(t, err, "parseColor(%q) should fail", bad)
+	}
+}
+
+func TestRunConvert_WithFitPad(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	outPath := filepath.Join(dir, "fit_output.html")
+
+	flagWidth = 8
+	flagHeight = 4
+	flagOutput = outPath
+	flagNoHTML = true
+	flagFit = "pad"
+	flagGravity = "west"
+	flagPadColor = "#00ff00"
+
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, `<table width="8" height="4"`)
+	assert.Contains(t, content, `background-color:#00ff00`)
+
+	// Reset
+	flagHeight = 0
+	flagFit = "stretch"
+	flagGravity = "center"
+	flagPadColor = "transparent"
+}
+
+func TestRunConvert_InvalidPadColor(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+
+	flagOutput = filepath.Join(dir, "out.html")
+	flagPadColor = "not-a-colour"
+
+	err := runConvert(nil, []string{imgPath})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --pad-color")
+
+	flagPadColor = "transparent"
+}
+
+// --- Cell size CLI tests ---
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input string
+		w, h  int
+	}{
+		{"1x1", 1, 1},
+		{"4", 4, 4},
+		{"2x1", 2, 1},
+		{" 3X5 ", 3, 5},
+	}
+	for _, tt := range tests {
+		w, h, err := parseSize(tt.input)
+		require.NoError(t, err, "parseSize(%q)", tt.input)
+		assert.Equal(t, tt.w, w)
+		assert.Equal(t, tt.h, h)
+	}
+
+	for _, bad := range []string{"", "x", "0x4", "-1", "axb", "2x"} {
+		_, _, err := parseSize(bad)
+		assert.Error(t, err, "parseSize(%q) should fail", bad)
+	}
+}
+
+func TestRunConvert_WithCellSize(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	outPath := filepath.Join(dir, "cells.html")
+
+	flagWidth = 4
+	flagHeight = 0
+	flagOutput = outPath
+	flagNoHTML = true
+	flagCellSize = "2x3"
+
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<table width="8" height="12"`)
+
+	flagCellSize = "1x1"
+}
+
+func TestRunConvert_InvalidCellSize(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+
+	flagOutput = filepath.Join(dir, "out.html")
+	flagCellSize = "0x0"
+
+	err := runConvert(nil, []string{imgPath})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --cell-size")
+
+	flagCellSize = "1x1"
+}
+
+// --- Crop CLI tests ---
+
+func TestParseCrop(t *testing.T) {
+	r, err := parseCrop("")
+	require.NoError(t, err)
+	assert.True(t, r.Empty())
+
+	r, err = parseCrop("16, 32, 8, 4")
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(16, 32, 24, 36), r)
+
+	for _, bad := range []string{"1,2,3", "a,b,c,d", "0,0,0,4", "-1,0,4,4"} {
+		_, err := parseCrop(bad)
+		assert.Error(t, err, "parseCrop(%q) should fail", bad)
+	}
+}
+
+func TestRunConvert_WithCrop(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	outPath := filepath.Join(dir, "crop.html")
+
+	flagWidth = 2
+	flagHeight = 0
+	flagOutput = outPath
+	flagNoHTML = true
+	flagCrop = "1,1,2,1"
+
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<table width="2" height="1"`)
+
+	flagCrop = ""
+}
+
+func TestRunConvert_InvalidCrop(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+
+	flagOutput = filepath.Join(dir, "out.html")
+	flagCrop = "1,2"
+
+	err := runConvert(nil, []string{imgPath})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --crop")
+
+	flagCrop = ""
+}
+
+// --- Sprites CLI tests ---
+
+// createTestSheet writes a 2x1 sheet of 4x4 tiles separated by a transparent
+// 1px gutter to the given path.
+func createTestSheet(t *testing.T, path string) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 9, 4))
+	for y := range 4 {
+		for x := range 4 {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+			img.Set(x+5, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, png.Encode(f, img))
+}
+
+// resetSpritesFlags restores every flag of the sprites command, including
+// the converter flags it shares with convert and animate, to its default now
+// and again when the test ends, so tests do not depend on their order.
+func resetSpritesFlags(t *testing.T) {
+	reset := func() {
+		spritesCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				require.NoError(t, sv.Replace(nil))
+			} else {
+				require.NoError(t, f.Value.Set(f.DefValue))
+			}
+			f.Changed = false
+		})
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestRunSprites_Page(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "sheet.png")
+	createTestSheet(t, sheetPath)
+	resetSpritesFlags(t)
+	flagSpritesOutput = filepath.Join(dir, "sprites.html")
+
+	require.NoError(t, runSprites(spritesCmd, []string{sheetPath}))
+
+	data, err := os.ReadFile(flagSpritesOutput)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, `<section id="sprite-0-0"`)
+	assert.Contains(t, content, `<section id="sprite-0-1"`)
+	assert.Equal(t, 2, strings.Count(content, `<table width="4" height="4"`), "native size by default")
+}
+
+func TestRunSprites_Grid(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "sheet.png")
+	createTestSheet(t, sheetPath)
+	resetSpritesFlags(t)
+	flagSpritesGrid = "3x4"
+	flagSpritesOutput = filepath.Join(dir, "grid.html")
+
+	require.NoError(t, runSprites(spritesCmd, []string{sheetPath}))
+
+	data, err := os.ReadFile(flagSpritesOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), `class="pixcel-sprite"`))
+}
+
+func TestRunSprites_Files(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "sheet.png")
+	createTestSheet(t, sheetPath)
+	resetSpritesFlags(t)
+	flagSpritesMode = "files"
+	flagSpritesDir = filepath.Join(dir, "tiles")
+
+	require.NoError(t, runSprites(spritesCmd, []string{sheetPath}))
+
+	for _, name := range []string{"sprite-0-0.html", "sprite-0-1.html"} {
+		data, err := os.ReadFile(filepath.Join(flagSpritesDir, name))
+		require.NoError(t, err, name)
+		assert.Contains(t, string(data), `<table width="4" height="4"`)
+	}
+}
+
+func TestRunSprites_Animate(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "sheet.png")
+	createTestSheet(t, sheetPath)
+	resetSpritesFlags(t)
+	flagSpritesMode = "animate"
+	flagSpritesDelay = 200 * time.Millisecond
+	flagSpritesOutput = filepath.Join(dir, "anim.html")
+
+	require.NoError(t, runSprites(spritesCmd, []string{sheetPath}))
+
+	data, err := os.ReadFile(flagSpritesOutput)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Equal(t, 2, strings.Count(content, `class="pixcel-frame"`))
+	assert.Contains(t, content, "0.400s")
+}
+
+func TestRunSprites_Errors(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "sheet.png")
+	createTestSheet(t, sheetPath)
+	resetSpritesFlags(t)
+	flagSpritesOutput = filepath.Join(dir, "out.html")
+
+	flagSpritesMode = "bogus"
+	assert.ErrorContains(t, runSprites(spritesCmd, []string{sheetPath}), "invalid --mode")
+	flagSpritesMode = "page"
+
+	assert.Error(t, runSprites(spritesCmd, []string{filepath.Join(dir, "missing.png")}))
+
+	flagSpritesGrid = "0x0"
+	assert.ErrorContains(t, runSprites(spritesCmd, []string{sheetPath}), "invalid --grid")
+	flagSpritesGrid = "auto"
+
+	flagCrop = "bad"
+	assert.ErrorContains(t, runSprites(spritesCmd, []string{sheetPath}), "invalid --crop")
+	flagCrop = ""
+
+	flagSpritesOutput = "/nonexistent/dir/out.html"
+	assert.ErrorContains(t, runSprites(spritesCmd, []string{sheetPath}), "failed to create output file")
+
+	flagSpritesMode = "files"
+	blocker := filepath.Join(dir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	flagSpritesDir = filepath.Join(blocker, "tiles")
+	assert.ErrorContains(t, runSprites(spritesCmd, []string{sheetPath}), "failed to create output directory")
+
+	resetSpritesFlags(t)
+}
+
+func TestRunSprites_MixedSizes(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "mixed.png")
+	sheet := image.NewRGBA(image.Rect(0, 0, 14, 8))
+	for y := range 8 {
+		for x := range 8 {
+			sheet.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	for y := range 4 {
+		for x := 10; x < 14; x++ {
+			sheet.Set(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+	f, err := os.Create(sheetPath)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, sheet))
+	require.NoError(t, f.Close())
+
+	resetSpritesFlags(t)
+	flagSpritesOutput = filepath.Join(dir, "mixed.html")
+	require.NoError(t, runSprites(spritesCmd, []string{sheetPath}))
+
+	data, err := os.ReadFile(flagSpritesOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<table width="8" height="8"`)
+	assert.Contains(t, string(data), `<table width="4" height="4"`)
+
+	// A height alone also overrides the native size.
+	require.NoError(t, spritesCmd.Flags().Set("height", "2"))
+	require.NoError(t, runSprites(spritesCmd, []string{sheetPath}))
+	data, err = os.ReadFile(flagSpritesOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), `height="2"`))
+	assert.NotContains(t, string(data), `height="8"`)
+	assert.NotContains(t, string(data), `height="4"`)
+}
+
+func TestExecute_SpritesSubcommand(t *testing.T) {
+	dir := t.TempDir()
+	sheetPath := filepath.Join(dir, "sheet.png")
+	createTestSheet(t, sheetPath)
+	outPath := filepath.Join(dir, "exec_sprites.html")
+	resetSpritesFlags(t)
+
+	rootCmd.SetArgs([]string{"sprites", sheetPath, "--grid", "auto", "-W", "8", "-o", outPath})
+	Execute()
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), `<table width="8" height="8"`))
+}
+
+// --- BMP, TIFF and WebP input tests ---
+
+// 2x2 solid red and blue VP8L bitstreams (simple single-symbol prefix codes).
+var (
+	testVP8LRed  = []byte{0x2f, 0x01, 0x40, 0x00, 0x10, 0x28, 0x40, 0xff, 0x0b, 0xd0, 0xff, 0x02, 0x00}
+	testVP8LBlue = []byte{0x2f, 0x01, 0x40, 0x00, 0x10, 0x28, 0x40, 0x01, 0xfa, 0xdf, 0xff, 0x02, 0x00}
+)
+
+// testChunk encodes a RIFF chunk, padding the body to an even length.
+func testChunk(id string, body []byte) []byte {
+	out := append([]byte(id), byte(len(body)), byte(len(body)>>8), byte(len(body)>>16), byte(len(body)>>24))
+	out = append(out, body...)
+	if len(body)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+// createTestWebP writes a 2x2 WebP to path. With more than one bitstream it
+// writes an animated WebP with one 100ms full-canvas frame per bitstream.
+func createTestWebP(t *testing.T, path string, frames ...[]byte) {
+	t.Helper()
+	var body []byte
+	if len(frames) == 1 {
+		body = testChunk("VP8L", frames[0])
+	} else {
+		body = append(body, testChunk("VP8X", []byte{0x12, 0, 0, 0, 1, 0, 0, 1, 0, 0})...)
+		body = append(body, testChunk("ANIM", make([]byte, 6))...)
+		for _, f := range frames {
+			header := []byte{0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 0, 0, 100, 0, 0, 0}
+			body = append(body, testChunk("ANMF", append(header, testChunk("VP8L", f)...))...)
+		}
+	}
+	require.NoError(t, os.WriteFile(path, testChunk("RIFF", append([]byte("WEBP"), body...)), 0644))
+}
+
+func TestLoadImage_ExtraFormats(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	encoders := map[string]func(io.Writer, image.Image) error{
+		"bmp":  bmp.Encode,
+		"tiff": func(w io.Writer, m image.Image) error { return tiff.Encode(w, m, nil) },
+	}
+	for format, encode := range encoders {
+		path := filepath.Join(dir, "test."+format)
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, encode(f, img))
+		require.NoError(t, f.Close())
+
+		_, got, err := loadImage(path)
+		require.NoError(t, err, format)
+		assert.Equal(t, format, got)
+	}
+
+	webpPath := filepath.Join(dir, "test.webp")
+	createTestWebP(t, webpPath, testVP8LRed)
+	_, got, err := loadImage(webpPath)
+	require.NoError(t, err)
+	assert.Equal(t, "webp", got)
+}
+
+func TestIsWebP(t *testing.T) {
+	dir := t.TempDir()
+	webpPath := filepath.Join(dir, "test.webp")
+	createTestWebP(t, webpPath, testVP8LRed)
+	pngPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, pngPath)
+	shortPath := filepath.Join(dir, "short")
+	require.NoError(t, os.WriteFile(shortPath, []byte("RIFF"), 0644))
+
+	assert.True(t, isWebP(webpPath))
+	assert.False(t, isWebP(pngPath))
+	assert.False(t, isWebP(shortPath))
+	assert.False(t, isWebP(filepath.Join(dir, "missing.webp")))
+}
+
+func TestRunConvert_WebP(t *testing.T) {
+	dir := t.TempDir()
+	flagWidth = 2
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+
+	stillPath := filepath.Join(dir, "still.webp")
+	createTestWebP(t, stillPath, testVP8LRed)
+	flagOutput = filepath.Join(dir, "still.html")
+	require.NoError(t, runConvert(nil, []string{stillPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "#ff0000")
+	assert.NotContains(t, string(data), "pixcel-frame")
+
+	animPath := filepath.Join(dir, "anim.webp")
+	createTestWebP(t, animPath, testVP8LRed, testVP8LBlue)
+	flagOutput = filepath.Join(dir, "anim.html")
+	require.NoError(t, runConvert(nil, []string{animPath}))
+	data, err = os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), `class="pixcel-frame"`))
+	assert.Contains(t, string(data), "#0000ff")
+}
+
+func TestRunConvert_WebPErrors(t *testing.T) {
+	dir := t.TempDir()
+	flagWidth = 2
+	flagCrop = ""
+
+	badPath := filepath.Join(dir, "bad.webp")
+	require.NoError(t, os.WriteFile(badPath, testChunk("RIFF", []byte("WEBPjunk")), 0644))
+	flagOutput = filepath.Join(dir, "bad.html")
+	assert.ErrorContains(t, runConvert(nil, []string{badPath}), "conversion failed")
+
+	goodPath := filepath.Join(dir, "good.webp")
+	createTestWebP(t, goodPath, testVP8LRed)
+	flagOutput = "/nonexistent/dir/out.html"
+	assert.ErrorContains(t, runConvert(nil, []string{goodPath}), "failed to create output file")
+}
+
+// --- APNG input tests ---
+
+// writeTestPNGChunk appends a PNG chunk with its length and CRC to buf.
+func writeTestPNGChunk(buf *bytes.Buffer, typ string, data []byte) {
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(data))))
+	buf.WriteString(typ)
+	buf.Write(data)
+	buf.Write(binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(append([]byte(typ), data...))))
+}
+
+// createTestAPNG writes a 2x2 APNG with one full-canvas 100ms frame per colour.
+func createTestAPNG(t *testing.T, path string, colors ...color.NRGBA) {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	writeTestPNGChunk(&buf, "IHDR", []byte{0, 0, 0, 2, 0, 0, 0, 2, 8, 6, 0, 0, 0})
+	writeTestPNGChunk(&buf, "acTL", binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, uint32(len(colors))), 0))
+
+	seq := uint32(0)
+	for i, c := range colors {
+		fctl := binary.BigEndian.AppendUint32(nil, seq)
+		fctl = append(fctl, 0, 0, 0, 2, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 10, 0, 0)
+		writeTestPNGChunk(&buf, "fcTL", fctl)
+		seq++
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		for range 2 {
+			_, err := zw.Write([]byte{0, c.R, c.G, c.B, c.A, c.R, c.G, c.B, c.A})
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+
+		if i == 0 {
+			writeTestPNGChunk(&buf, "IDAT", z.Bytes())
+			continue
+		}
+		writeTestPNGChunk(&buf, "fdAT", append(binary.BigEndian.AppendUint32(nil, seq), z.Bytes()...))
+		seq++
+	}
+	writeTestPNGChunk(&buf, "IEND", nil)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestIsAPNG(t *testing.T) {
+	dir := t.TempDir()
+	apngPath := filepath.Join(dir, "anim.png")
+	createTestAPNG(t, apngPath, color.NRGBA{R: 255, A: 255}, color.NRGBA{B: 255, A: 255})
+	pngPath := filepath.Join(dir, "still.png")
+	createTestPNG(t, pngPath)
+	truncPath := filepath.Join(dir, "trunc.png")
+	require.NoError(t, os.WriteFile(truncPath, []byte("\x89PNG\r\n\x1a\n\x00\x00"), 0644))
+
+	assert.True(t, isAPNG(apngPath))
+	assert.False(t, isAPNG(pngPath))
+	assert.False(t, isAPNG(truncPath))
+	assert.False(t, isAPNG(filepath.Join(dir, "missing.png")))
+	assert.False(t, isAPNG(filepath.Join(dir, "..")))
+}
+
+func TestRunConvert_APNG(t *testing.T) {
+	dir := t.TempDir()
+	apngPath := filepath.Join(dir, "anim.png")
+	createTestAPNG(t, apngPath, color.NRGBA{R: 255, A: 255}, color.NRGBA{B: 255, A: 255})
+	flagWidth = 2
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "anim.html")
+
+	require.NoError(t, runConvert(nil, []string{apngPath}))
+
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Equal(t, 2, strings.Count(content, `class="pixcel-frame"`))
+	assert.Contains(t, content, "#ff0000")
+	assert.Contains(t, content, "#0000ff")
+	assert.Contains(t, content, "0.200s")
+}
+
+// --- Animate CLI tests ---
+
+// createFrameFiles writes n 2x2 PNG frames named frame<i>.png to dir, each a
+// distinct shade of red, and returns their paths.
+func createFrameFiles(t *testing.T, dir string, n int) []string {
+	t.Helper()
+	var paths []string
+	for i := range n {
+		img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		for y := range 2 {
+			for x := range 2 {
+				img.Set(x, y, color.RGBA{R: uint8(100 + i*50), A: 255})
+			}
+		}
+		path := filepath.Join(dir, fmt.Sprintf("frame%d.png", i))
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, png.Encode(f, img))
+		require.NoError(t, f.Close())
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func resetAnimateFlags() {
+	flagAnimateFPS = 10
+	flagAnimateFrameDelay = nil
+	flagLoop = -1
+	flagAnimatePingPong = false
+	flagWidth = 2
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagMaxFrames = 10
+}
+
+func TestRunAnimate_FileList(t *testing.T) {
+	dir := t.TempDir()
+	paths := createFrameFiles(t, dir, 3)
+	resetAnimateFlags()
+	flagAnimateFPS = 4
+	flagAnimateFrameDelay = []string{"1=1s"}
+	flagAnimateOutput = filepath.Join(dir, "anim.html")
+
+	require.NoError(t, runAnimate(nil, paths))
+
+	data, err := os.ReadFile(flagAnimateOutput)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Equal(t, 3, strings.Count(content, `class="pixcel-frame"`))
+	// 250ms + 1s override + 250ms.
+	assert.Contains(t, content, "1.500s")
+	assert.Contains(t, content, "step-end infinite")
+}
+
+func TestRunAnimate_DirectoryPingPongLoop(t *testing.T) {
+	dir := t.TempDir()
+	createFrameFiles(t, dir, 3)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skip me"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	resetAnimateFlags()
+	flagAnimatePingPong = true
+	flagLoop = 2
+	flagAnimateOutput = filepath.Join(t.TempDir(), "pingpong.html")
+
+	require.NoError(t, runAnimate(nil, []string{dir}))
+
+	data, err := os.ReadFile(flagAnimateOutput)
+	require.NoError(t, err)
+	content := string(data)
+	// 0 1 2 then 1 back, which reuses the layer of frame 1.
+	assert.Equal(t, 3, strings.Count(content, `class="pixcel-frame"`))
+	assert.Contains(t, content, "@keyframes pixcel-anim-1 {\n    0% { opacity: 0; }\n    25.0000% { opacity: 1; }\n    50.0000% { opacity: 0; }\n    75.0000% { opacity: 1; }\n    100% { opacity: 1; }\n  }")
+	assert.Contains(t, content, "0.400s")
+	assert.Contains(t, content, "step-end 2 forwards")
+}
+
+func TestRunAnimate_Glob(t *testing.T) {
+	dir := t.TempDir()
+	createFrameFiles(t, dir, 2)
+	resetAnimateFlags()
+	flagAnimateOutput = filepath.Join(dir, "glob.html")
+
+	require.NoError(t, runAnimate(nil, []string{filepath.Join(dir, "frame*.png")}))
+
+	data, err := os.ReadFile(flagAnimateOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), `class="pixcel-frame"`))
+}
+
+func TestRunAnimate_Errors(t *testing.T) {
+	dir := t.TempDir()
+	paths := createFrameFiles(t, dir, 2)
+	resetAnimateFlags()
+	flagAnimateOutput = filepath.Join(dir, "out.html")
+
+	flagAnimateFPS = 0
+	assert.ErrorContains(t, runAnimate(nil, paths), "invalid --fps")
+	flagAnimateFPS = 10
+
+	flagLoop = -2
+	assert.ErrorContains(t, runAnimate(nil, paths), "invalid --loop")
+	flagLoop = -1
+
+	assert.ErrorContains(t, runAnimate(nil, []string{filepath.Join(dir, "nothing*.png")}), "no frame images found")
+	assert.ErrorContains(t, runAnimate(nil, []string{"/nonexistent/x[.png"}), "invalid pattern")
+	assert.ErrorContains(t, runAnimate(nil, []string{paths[0], filepath.Join(dir, "missing.png")}), "frame 1")
+
+	flagAnimateFrameDelay = []string{"5=1s"}
+	assert.ErrorContains(t, runAnimate(nil, paths), "frame index")
+	flagAnimateFrameDelay = nil
+
+	flagCrop = "bad"
+	assert.ErrorContains(t, runAnimate(nil, paths), "invalid --crop")
+	flagCrop = "50,50,1,1"
+	assert.ErrorContains(t, runAnimate(nil, paths), "conversion failed")
+	flagCrop = ""
+
+	flagAnimateOutput = "/nonexistent/dir/out.html"
+	assert.ErrorContains(t, runAnimate(nil, paths), "failed to create output file")
+
+	resetAnimateFlags()
+}
+
+func TestParseFrameDelays(t *testing.T) {
+	got, err := parseFrameDelays([]string{"0=500ms", " 2 = 1s "}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]time.Duration{0: 500 * time.Millisecond, 2: time.Second}, got)
+
+	for _, bad := range []string{"0", "x=1s", "-1=1s", "3=1s", "0=soon", "0=-1s"} {
+		_, err := parseFrameDelays([]string{bad}, 3)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPingPong(t *testing.T) {
+	frames := []image.Image{image.NewRGBA(image.Rect(0, 0, 1, 1)), image.NewRGBA(image.Rect(0, 0, 2, 2)), image.NewRGBA(image.Rect(0, 0, 3, 3))}
+	a := &pixcel.Animation{Frames: frames, Delays: []time.Duration{1, 2, 3}}
+	pingPong(a)
+	assert.Equal(t, []time.Duration{1, 2, 3, 2}, a.Delays)
+	assert.Same(t, frames[1], a.Frames[3])
+
+	single := &pixcel.Animation{Frames: frames[:1], Delays: []time.Duration{1}}
+	pingPong(single)
+	assert.Len(t, single.Frames, 1)
+}
+
+func TestDirFrames_Missing(t *testing.T) {
+	_, err := dirFrames(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "failed to read directory")
+}
+
+func TestExecute_AnimateSubcommand(t *testing.T) {
+	dir := t.TempDir()
+	paths := createFrameFiles(t, dir, 2)
+	outPath := filepath.Join(dir, "exec_anim.html")
+	resetAnimateFlags()
+
+	rootCmd.SetArgs(append([]string{"animate", "--fps", "5", "-W", "2", "-o", outPath}, paths...))
+	Execute()
+
+	data, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "0.400s")
+	resetAnimateFlags()
+}
+
+func TestRunConvert_AnimatedGIFLoop(t *testing.T) {
+	dir := t.TempDir()
+	gifPath := filepath.Join(dir, "animated.gif")
+	createTestGIFFile(t, gifPath, 3)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "loop.html")
+
+	flagLoop = 1
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), "step-end 1 forwards;"))
+
+	flagLoop = -1
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err = os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), "step-end infinite;"), "source loop count kept")
+
+	flagLoop = -5
+	assert.ErrorContains(t, runConvert(nil, []string{gifPath}), "invalid --loop")
+	flagLoop = -1
+}
+
+func TestRunConvert_FrameDelta(t *testing.T) {
+	dir := t.TempDir()
+	gifPath := filepath.Join(dir, "animated.gif")
+	createTestGIFFile(t, gifPath, 3)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "delta.html")
+
+	flagFrameDelta = true
+	defer func() { flagFrameDelta = false }()
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "<table"), "later frames are patches")
+	assert.Contains(t, string(data), "position:absolute;left:0px;top:0px;width:4px;height:4px;")
+}
+
+func TestRunConvert_SamplingDuration(t *testing.T) {
+	dir := t.TempDir()
+	gifPath := filepath.Join(dir, "animated.gif")
+	createTestGIFFile(t, gifPath, 6)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "sampled.html")
+	flagMaxFrames = 3
+	defer func() { flagMaxFrames, flagSampling, flagSampleFPS = 10, "uniform", 10 }()
+
+	flagSampling = "duration"
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "0.600s", "total duration kept")
+
+	flagSampleFPS = 0
+	assert.ErrorContains(t, runConvert(nil, []string{gifPath}), "invalid --sample-fps")
+}
+
+func TestRunConvert_Player(t *testing.T) {
+	dir := t.TempDir()
+	gifPath := filepath.Join(dir, "animated.gif")
+	createTestGIFFile(t, gifPath, 3)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "player.html")
+
+	flagPlayer = true
+	defer func() { flagPlayer = false }()
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `data-pixcel="seek"`)
+	assert.NotContains(t, string(data), "@keyframes")
+}
+
+func TestRunConvert_ReducedMotion(t *testing.T) {
+	dir := t.TempDir()
+	gifPath := filepath.Join(dir, "animated.gif")
+	createTestGIFFile(t, gifPath, 3)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "still.html")
+	defer func() { flagPoster = "" }()
+
+	flagPoster = "last"
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "@media (prefers-reduced-motion: reduce)")
+	assert.Contains(t, string(data), ".pixcel-frame:nth-child(3) { opacity: 1; }")
+
+	flagPoster = "middle"
+	assert.ErrorContains(t, runConvert(nil, []string{gifPath}), "invalid --reduced-motion")
+}
+
+func TestRunConvert_Template(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	tmplPath := filepath.Join(dir, "page.tmpl")
+	require.NoError(t, os.WriteFile(tmplPath, []byte(`{{define "image"}}<figure>{{.Width}}x{{.Height}} {{mul .Width 2}}</figure>{{end}}`), 0644))
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "custom.html")
+	defer func() { flagTemplate = "" }()
+
+	flagTemplate = tmplPath
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Equal(t, "<figure>4x4 8</figure>", string(data))
+
+	flagTemplate = filepath.Join(dir, "missing.tmpl")
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --template")
+
+	require.NoError(t, os.WriteFile(tmplPath, []byte(`{{define "page"}}{{end}}`), 0644))
+	flagTemplate = tmplPath
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "defines neither")
+}
+
+func TestRunConvert_Theme(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "themed.html")
+	defer func() {
+		flagTheme, flagBackground, flagShadow, flagAlign = "dark", "", "", ""
+		flagPadding, flagRadius = -1, -1
+	}()
+
+	flagTheme = "light"
+	flagBackground = "#abcdef"
+	flagPadding = 0
+	flagShadow = "none"
+	flagAlign = "top-left"
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	html := string(data)
+	assert.Contains(t, html, "background: #abcdef;")
+	assert.Contains(t, html, "background: #ffffff;", "light surface kept")
+	assert.Contains(t, html, "padding: 0px;")
+	assert.Contains(t, html, "border-radius: 12px;", "preset radius kept")
+	assert.Contains(t, html, "box-shadow: none;")
+	assert.Contains(t, html, "display: inline-block;")
+	assert.NotContains(t, html, "justify-content")
+
+	flagAlign = "middle"
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --align")
+	flagAlign = ""
+
+	flagTheme = "drak"
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), `invalid --theme "drak" (expected dark, light or transparent)`)
+	flagTheme = "DARK"
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	flagTheme = "light"
+
+	flagBackground = "red;}</style>"
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --background")
+	flagBackground = ""
+
+	flagRadius = -2
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --radius")
+}
+
+func TestRunConvert_WebComponent(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "snippet.html")
+
+	flagComponent = true
+	defer func() { flagComponent = false }()
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "<pixcel-art>\n<template>"))
+	assert.Contains(t, string(data), `customElements.define("pixcel-art"`)
+	assert.NotContains(t, string(data), "<!DOCTYPE html>")
+}
+
+func TestRunConvert_ClassPrefix(t *testing.T) {
+	dir := t.TempDir()
+	gifPath := filepath.Join(dir, "animated.gif")
+	createTestGIFFile(t, gifPath, 2)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = true
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "fragment.html")
+	defer func() { flagNoHTML, flagPrefix, flagUnique = false, "pixcel", false }()
+
+	flagPrefix = "logo"
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<style>")
+	assert.Contains(t, string(data), "@keyframes logo-anim-0")
+	assert.Contains(t, string(data), `class="logo-frame"`)
+
+	flagUnique = true
+	require.NoError(t, runConvert(nil, []string{gifPath}))
+	data, err = os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Regexp(t, `@keyframes logo-[0-9a-f]{6}-anim-0`, string(data))
+}
+
+func TestRunConvert_Responsive(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "responsive.html")
+	defer func() { flagResponsive, flagMinWidth, flagMaxWidth = false, 0, 0 }()
+
+	flagResponsive = true
+	flagMaxWidth = 320
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "container-type:inline-size;width:100%;max-width:320px")
+	assert.Contains(t, string(data), "width:4em;height:4em;")
+
+	flagMinWidth = -1
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --min-width")
+}
+
+func TestRunConvert_AltText(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "alt.html")
+	defer func() { flagAlt, flagDesc = "", "" }()
+
+	flagAlt = "Red square"
+	flagDesc = "A solid red square"
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<div role="img" aria-label="Red square" aria-describedby="pixcel-desc">`)
+	assert.Contains(t, string(data), `role="presentation"`)
+	assert.Contains(t, string(data), `<figcaption id="pixcel-desc">A solid red square</figcaption>`)
+
+	flagAlt = ""
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "--description requires --alt")
+}
+
+func TestRunConvert_Minify(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "pretty.html")
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	pretty, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+
+	flagMinify = true
+	defer func() { flagMinify = false }()
+	flagOutput = filepath.Join(dir, "min.html")
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.Less(t, len(data), len(pretty))
+	assert.NotContains(t, string(data), "</td>")
+	assert.Contains(t, string(data), "<html lang=en>")
+}
+
+func TestRunConvert_Compress(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "art.html")
+	defer func() { flagCompress = nil }()
+
+	flagCompress = []string{"gzip", "br"}
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	html, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+
+	gz, err := os.Open(flagOutput + ".gz")
+	require.NoError(t, err)
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, html, data)
+
+	br, err := os.Open(flagOutput + ".br")
+	require.NoError(t, err)
+	defer br.Close()
+	data, err = io.ReadAll(brotli.NewReader(br))
+	require.NoError(t, err)
+	assert.Equal(t, html, data)
+
+	flagCompress = []string{"zstd"}
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --compress")
+}
+
+func TestRunConvert_MaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagOutput = filepath.Join(dir, "budget.html")
+	defer func() { flagMaxBytes = 0 }()
+
+	flagMaxBytes = 1 << 20
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(data), flagMaxBytes)
+
+	flagMaxBytes = 10
+	err = runConvert(nil, []string{imgPath})
+	assert.ErrorIs(t, err, pixcel.ErrMaxBytes)
+	var budgetErr *pixcel.MaxBytesError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, 10, budgetErr.Limit)
+
+	flagMaxBytes = -1
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --max-bytes")
+}
+
+func TestParseProfile(t *testing.T) {
+	for name, want := range map[string]pixcel.Profile{
+		"web":   pixcel.ProfileWeb,
+		"email": pixcel.ProfileEmail,
+		"EMAIL": pixcel.ProfileEmail,
+	} {
+		got, err := parseProfile(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := parseProfile("emial")
+	assert.ErrorContains(t, err, `unknown profile "emial"`)
+}
+
+func TestRunConvert_EmailProfile(t *testing.T) {
+	dir := t.TempDir()
+	imgPath := filepath.Join(dir, "test.png")
+	createTestPNG(t, imgPath)
+	flagWidth = 4
+	flagHeight = 0
+	flagNoHTML = false
+	flagCrop = ""
+	flagSmoothLoad = true
+	flagOutput = filepath.Join(dir, "email.html")
+	defer func() { flagProfile, flagSmoothLoad = "web", false }()
+
+	flagProfile = "email"
+	require.NoError(t, runConvert(nil, []string{imgPath}))
+	data, err := os.ReadFile(flagOutput)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, `bgcolor="#ff0000"`)
+	assert.Contains(t, content, `colspan="4"`)
+	assert.NotContains(t, content, "<style")
+	assert.NotContains(t, content, "<script")
+
+	flagProfile = "emial"
+	assert.ErrorContains(t, runConvert(nil, []string{imgPath}), "invalid --profile")
+}
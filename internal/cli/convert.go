@@ -8,25 +8,16 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"strings"
 
 	"github.com/H0llyW00dzZ/pixcel/src/pixcel"
 	"github.com/spf13/cobra"
-	"golang.org/x/image/draw"
 )
 
 // convertCmd flag values, scoped to this file.
 var (
-	flagWidth      int
-	flagHeight     int
-	flagOutput     string
-	flagNoHTML     bool
-	flagTitle      string
-	flagSmoothLoad bool
-	flagScaler     string
-	flagObfuscate  bool
-	flagMaxFrames  int
+	flagOutput string
 )
 
 // convertCmd converts an image file to HTML pixel art.
@@ -39,15 +30,8 @@ var convertCmd = &cobra.Command{
 }
 
 func init() {
-	convertCmd.Flags().IntVarP(&flagWidth, "width", "W", 56, "target width in table cells")
-	convertCmd.Flags().IntVarP(&flagHeight, "height", "H", 0, "target height in table cells (default: proportional)")
 	convertCmd.Flags().StringVarP(&flagOutput, "output", "o", "go_pixel_art.html", "output HTML file path")
-	convertCmd.Flags().BoolVar(&flagNoHTML, "no-html", false, "output only the <table>, omit the HTML wrapper")
-	convertCmd.Flags().StringVarP(&flagTitle, "title", "t", "Go Pixel Art", "title for the HTML page")
-	convertCmd.Flags().BoolVar(&flagSmoothLoad, "smooth-load", false, "hide content until fully loaded to prevent progressive rendering")
-	convertCmd.Flags().StringVar(&flagScaler, "scaler", "nearest", "scaling algorithm: nearest, catmullrom, bilinear, approxbilinear")
-	convertCmd.Flags().BoolVar(&flagObfuscate, "obfuscate", false, "randomize inline CSS styling formats for CAPTCHA/scraping protection")
-	convertCmd.Flags().IntVar(&flagMaxFrames, "max-frames", 10, "maximum number of GIF frames to process (excess frames are sampled uniformly)")
+	addConverterFlags(convertCmd)
 
 	rootCmd.AddCommand(convertCmd)
 }
@@ -56,74 +40,93 @@ func init() {
 func runConvert(_ *cobra.Command, args []string) error {
 	imagePath := args[0]
 
+	opts, err := converterOptions()
+	if err != nil {
+		return err
+	}
+	converter := pixcel.New(opts...)
+
 	// Try animated GIF path first.
 	if g, err := loadGIF(imagePath); err == nil && len(g.Image) > 1 {
 		fmt.Printf("Loaded animated gif (%d frames) from %s\n", len(g.Image), imagePath)
 
-		converter := pixcel.New(
-			pixcel.WithTargetWidth(flagWidth),
-			pixcel.WithTargetHeight(flagHeight),
-			pixcel.WithHTMLWrapper(!flagNoHTML, flagTitle),
-			pixcel.WithSmoothLoad(flagSmoothLoad),
-			pixcel.WithScaler(parseScaler(flagScaler)),
-			pixcel.WithObfuscation(flagObfuscate),
-			pixcel.WithMaxFrames(flagMaxFrames),
-		)
-
-		outFile, err := os.Create(flagOutput)
+		outFile, err := createOutput(flagOutput)
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return err
 		}
 		defer outFile.Close()
 
 		if err := converter.ConvertGIF(context.Background(), g, outFile); err != nil {
 			return fmt.Errorf("conversion failed: %w", err)
 		}
+		if err := outFile.Close(); err != nil {
+			return err
+		}
 
 		fmt.Printf("Done! Saved animated HTML pixel art to %s\n", flagOutput)
+		outFile.printSizes()
 		return nil
 	}
 
-	// Static image path (PNG, JPEG, single-frame GIF).
+	// Animated PNG path.
+	if isAPNG(imagePath) {
+		return convertReader(imagePath, "apng", converter.ConvertAPNG)
+	}
+
+	// WebP path, still or animated.
+	if isWebP(imagePath) {
+		return convertReader(imagePath, "webp", converter.ConvertWebP)
+	}
+
+	// Static image path (PNG, JPEG, BMP, TIFF, single-frame GIF).
 	img, format, err := loadImage(imagePath)
 	if err != nil {
 		return err
 	}
 	fmt.Printf("Loaded %s image from %s\n", format, imagePath)
 
-	converter := pixcel.New(
-		pixcel.WithTargetWidth(flagWidth),
-		pixcel.WithTargetHeight(flagHeight),
-		pixcel.WithHTMLWrapper(!flagNoHTML, flagTitle),
-		pixcel.WithSmoothLoad(flagSmoothLoad),
-		pixcel.WithScaler(parseScaler(flagScaler)),
-		pixcel.WithObfuscation(flagObfuscate),
-	)
-
-	outFile, err := os.Create(flagOutput)
+	outFile, err := createOutput(flagOutput)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
 	defer outFile.Close()
 
 	if err := converter.Convert(context.Background(), img, outFile); err != nil {
 		return fmt.Errorf("conversion failed: %w", err)
 	}
+	if err := outFile.Close(); err != nil {
+		return err
+	}
 
 	fmt.Printf("Done! Saved HTML pixel art to %s\n", flagOutput)
+	outFile.printSizes()
 	return nil
 }
 
-// parseScaler maps a CLI flag string to a [draw.Scaler] implementation.
-func parseScaler(name string) draw.Scaler {
-	switch strings.ToLower(name) {
-	case "catmullrom":
-		return draw.CatmullRom
-	case "bilinear":
-		return draw.BiLinear
-	case "approxbilinear":
-		return draw.ApproxBiLinear
-	default:
-		return draw.NearestNeighbor
+// convertReader converts a file through one of the SDK's reader-based paths
+// (ConvertWebP, ConvertAPNG), which handle both still and animated images.
+func convertReader(imagePath, format string, convert func(context.Context, io.Reader, io.Writer) error) error {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+	fmt.Printf("Loaded %s image from %s\n", format, imagePath)
+
+	outFile, err := createOutput(flagOutput)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	if err := convert(context.Background(), f, outFile); err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+	if err := outFile.Close(); err != nil {
+		return err
 	}
+
+	fmt.Printf("Done! Saved HTML pixel art to %s\n", flagOutput)
+	outFile.printSizes()
+	return nil
 }
@@ -0,0 +1,433 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package cli
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/H0llyW00dzZ/pixcel/src/pixcel"
+	"github.com/spf13/cobra"
+	"golang.org/x/image/draw"
+)
+
+// Converter flag values, shared by every command that renders pixel art.
+var (
+	flagWidth      int
+	flagHeight     int
+	flagNoHTML     bool
+	flagTitle      string
+	flagSmoothLoad bool
+	flagComponent  bool
+	flagPrefix     string
+	flagUnique     bool
+	flagResponsive bool
+	flagMinWidth   int
+	flagMaxWidth   int
+	flagAlt        string
+	flagDesc       string
+	flagMinify     bool
+	flagCompress   []string
+	flagMaxBytes   int
+	flagProfile    string
+	flagScaler     string
+	flagObfuscate  bool
+	flagMaxFrames  int
+	flagSampling   string
+	flagSampleFPS  float64
+	flagFit        string
+	flagGravity    string
+	flagPadColor   string
+	flagCellSize   string
+	flagCrop       string
+	flagLoop       int
+	flagFrameDelta bool
+	flagPlayer     bool
+	flagPoster     string
+	flagTemplate   string
+	flagTheme      string
+	flagBackground string
+	flagSurface    string
+	flagTextColor  string
+	flagPadding    int
+	flagRadius     int
+	flagShadow     string
+	flagAlign      string
+)
+
+// addConverterFlags registers the flags that map onto SDK options on cmd.
+// All commands share the same variables, so defaults must be identical.
+func addConverterFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&flagWidth, "width", "W", 56, "target width in table cells")
+	cmd.Flags().IntVarP(&flagHeight, "height", "H", 0, "target height in table cells (default: proportional)")
+	cmd.Flags().BoolVar(&flagNoHTML, "no-html", false, "output only the <table>, omit the HTML wrapper")
+	cmd.Flags().StringVarP(&flagTitle, "title", "t", "Go Pixel Art", "title for the HTML page")
+	cmd.Flags().BoolVar(&flagComponent, "web-component", false, "output a self-contained <pixcel-art> custom element using Shadow DOM instead of a page")
+	cmd.Flags().StringVar(&flagAlt, "alt", "", "text alternative announced by screen readers (marks the art as an image)")
+	cmd.Flags().StringVar(&flagDesc, "description", "", "longer description shown as a caption below the art (requires --alt)")
+	cmd.Flags().BoolVar(&flagMinify, "minify", false, "minify the HTML: drop whitespace, optional quotes and end tags, compact CSS")
+	cmd.Flags().StringVar(&flagProfile, "profile", "web", "output profile: web, email (table attributes only, no CSS or scripts, first or poster frame of animations)")
+	cmd.Flags().IntVar(&flagMaxBytes, "max-bytes", 0, "shrink the size, frame count and cell size until the HTML fits in this many bytes (0 = no limit)")
+	cmd.Flags().StringSliceVar(&flagCompress, "compress", nil, "also write pre-compressed copies of the output: gzip (.gz), br (.br), or both as gzip,br")
+	cmd.Flags().BoolVar(&flagResponsive, "responsive", false, "scale the art to the width of its container, keeping its aspect ratio")
+	cmd.Flags().IntVar(&flagMinWidth, "min-width", 0, "minimum rendered width in CSS pixels for --responsive (0 = none)")
+	cmd.Flags().IntVar(&flagMaxWidth, "max-width", 0, "maximum rendered width in CSS pixels for --responsive (0 = none)")
+	cmd.Flags().StringVar(&flagPrefix, "class-prefix", "pixcel", "prefix of CSS class and @keyframes names, to embed several outputs in one page")
+	cmd.Flags().BoolVar(&flagUnique, "unique-prefix", false, "append a random suffix to --class-prefix so every output has unique names")
+	cmd.Flags().BoolVar(&flagSmoothLoad, "smooth-load", false, "hide content until fully loaded to prevent progressive rendering")
+	cmd.Flags().StringVar(&flagScaler, "scaler", "nearest", "scaling algorithm: nearest, catmullrom, bilinear, approxbilinear")
+	cmd.Flags().BoolVar(&flagObfuscate, "obfuscate", false, "randomize inline CSS styling formats for CAPTCHA/scraping protection")
+	cmd.Flags().IntVar(&flagMaxFrames, "max-frames", 10, "maximum number of GIF frames to process (excess frames are sampled, see --sampling)")
+	cmd.Flags().StringVar(&flagSampling, "sampling", "uniform", "frame sampling beyond --max-frames: uniform, duration, distinct, fps")
+	cmd.Flags().Float64Var(&flagSampleFPS, "sample-fps", 10, "frame rate for --sampling fps")
+	cmd.Flags().IntVar(&flagLoop, "loop", -1, "number of times to play animations, 0 = forever (-1 keeps the source's loop count)")
+	cmd.Flags().BoolVar(&flagFrameDelta, "frame-delta", false, "render animation frames after the first as only the pixels that changed from it")
+	cmd.Flags().BoolVar(&flagPlayer, "player", false, "add play/pause, step, speed and frame slider controls to animations (uses JavaScript)")
+	cmd.Flags().StringVar(&flagPoster, "reduced-motion", "", "poster frame shown instead of animations for prefers-reduced-motion: first, last or a frame index")
+	cmd.Flags().StringVar(&flagTemplate, "template", "", "custom page template file defining \"image\" and/or \"animation\" templates")
+	cmd.Flags().StringVar(&flagTheme, "theme", "dark", "page styling preset: dark, light, transparent")
+	cmd.Flags().StringVar(&flagBackground, "background", "", "page background CSS colour (default: from --theme)")
+	cmd.Flags().StringVar(&flagSurface, "surface", "", "background CSS colour of the card holding the art (default: from --theme)")
+	cmd.Flags().StringVar(&flagTextColor, "text-color", "", "CSS colour of labels and player controls (default: from --theme)")
+	cmd.Flags().IntVar(&flagPadding, "padding", -1, "card padding in CSS pixels (-1 uses --theme)")
+	cmd.Flags().IntVar(&flagRadius, "radius", -1, "card corner radius in CSS pixels (-1 uses --theme)")
+	cmd.Flags().StringVar(&flagShadow, "shadow", "", "card CSS box-shadow, e.g. none (default: from --theme)")
+	cmd.Flags().StringVar(&flagAlign, "align", "", "card position in the page: center, top-left (default: from --theme)")
+	cmd.Flags().StringVar(&flagCrop, "crop", "", "convert only the region x,y,w,h of the source image (applied before scaling)")
+	cmd.Flags().StringVar(&flagFit, "fit", "stretch", "how to fit the image when both width and height are set: stretch, contain, cover, pad")
+	cmd.Flags().StringVar(&flagGravity, "gravity", "center", "anchor for --fit cover/pad: center, north, south, east, west, northeast, northwest, southeast, southwest")
+	cmd.Flags().StringVar(&flagCellSize, "cell-size", "1x1", "CSS pixel size of each cell as WxH or N (e.g. 4, 2x1 for wide pixels)")
+	cmd.Flags().StringVar(&flagPadColor, "pad-color", "transparent", "padding colour for --fit pad: transparent or hex (#rgb, #rrggbb, #rrggbbaa)")
+}
+
+// converterOptions translates the convert flags into SDK options.
+func converterOptions() ([]pixcel.Option, error) {
+	padColor, err := parseColor(flagPadColor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --pad-color: %w", err)
+	}
+	crop, err := parseCrop(flagCrop)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --crop: %w", err)
+	}
+	cellW, cellH, err := parseSize(flagCellSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --cell-size: %w", err)
+	}
+	poster, reduceMotion, err := parsePoster(flagPoster)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --reduced-motion: %w", err)
+	}
+	page, err := parseTemplate(flagTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	theme, err := parseTheme()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := parseProfile(flagProfile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --profile: %w", err)
+	}
+	if flagMaxBytes < 0 {
+		return nil, fmt.Errorf("invalid --max-bytes %d (must not be negative)", flagMaxBytes)
+	}
+	if _, err := parseCompress(flagCompress); err != nil {
+		return nil, fmt.Errorf("invalid --compress: %w", err)
+	}
+	if flagSampleFPS <= 0 {
+		return nil, fmt.Errorf("invalid --sample-fps %v (must be positive)", flagSampleFPS)
+	}
+	if flagDesc != "" && flagAlt == "" {
+		return nil, fmt.Errorf("--description requires --alt")
+	}
+	if flagMinWidth < 0 || flagMaxWidth < 0 {
+		return nil, fmt.Errorf("invalid --min-width/--max-width %d/%d (must not be negative)", flagMinWidth, flagMaxWidth)
+	}
+	if flagLoop < -1 {
+		return nil, fmt.Errorf("invalid --loop %d (must be -1 or more)", flagLoop)
+	}
+
+	return []pixcel.Option{
+		pixcel.WithTargetWidth(flagWidth),
+		pixcel.WithTargetHeight(flagHeight),
+		pixcel.WithHTMLWrapper(!flagNoHTML, flagTitle),
+		pixcel.WithWebComponent(flagComponent),
+		pixcel.WithAltText(flagAlt, flagDesc),
+		pixcel.WithMinify(flagMinify),
+		pixcel.WithMaxBytes(flagMaxBytes, printBudget),
+		pixcel.WithResponsive(flagResponsive, flagMinWidth, flagMaxWidth),
+		pixcel.WithClassPrefix(flagPrefix),
+		pixcel.WithUniquePrefix(flagUnique),
+		pixcel.WithSmoothLoad(flagSmoothLoad),
+		pixcel.WithScaler(parseScaler(flagScaler)),
+		pixcel.WithObfuscation(flagObfuscate),
+		pixcel.WithMaxFrames(flagMaxFrames),
+		pixcel.WithFrameSampling(parseSampling(flagSampling)),
+		pixcel.WithSampleFPS(flagSampleFPS),
+		pixcel.WithLoopCount(flagLoop),
+		pixcel.WithFrameDelta(flagFrameDelta),
+		pixcel.WithPlayer(flagPlayer),
+		pixcel.WithReducedMotion(reduceMotion, poster),
+		pixcel.WithTemplate(page),
+		pixcel.WithTheme(theme),
+		pixcel.WithCrop(crop),
+		pixcel.WithFit(parseFit(flagFit)),
+		pixcel.WithProfile(profile),
+		pixcel.WithGravity(parseGravity(flagGravity)),
+		pixcel.WithPadColor(padColor),
+		pixcel.WithCellSize(cellW, cellH),
+	}, nil
+}
+
+// parseScaler maps a CLI flag string to a [draw.Scaler] implementation.
+func parseScaler(name string) draw.Scaler {
+	switch strings.ToLower(name) {
+	case "catmullrom":
+		return draw.CatmullRom
+	case "bilinear":
+		return draw.BiLinear
+	case "approxbilinear":
+		return draw.ApproxBiLinear
+	default:
+		return draw.NearestNeighbor
+	}
+}
+
+// parseFit maps a CLI flag string to a [pixcel.Fit] mode.
+func parseFit(name string) pixcel.Fit {
+	switch strings.ToLower(name) {
+	case "contain":
+		return pixcel.FitContain
+	case "cover":
+		return pixcel.FitCover
+	case "pad":
+		return pixcel.FitPad
+	default:
+		return pixcel.FitStretch
+	}
+}
+
+// parseProfile maps a CLI flag string to a [pixcel.Profile]. Unknown names
+// are rejected rather than falling back to the web profile, which would break
+// the guarantees of the email profile on a typo.
+func parseProfile(name string) (pixcel.Profile, error) {
+	switch strings.ToLower(name) {
+	case "web":
+		return pixcel.ProfileWeb, nil
+	case "email":
+		return pixcel.ProfileEmail, nil
+	default:
+		return pixcel.ProfileWeb, fmt.Errorf("unknown profile %q (want web or email)", name)
+	}
+}
+
+// parseSampling maps a CLI flag string to a [pixcel.FrameSampling] strategy.
+func parseSampling(name string) pixcel.FrameSampling {
+	switch strings.ToLower(name) {
+	case "duration":
+		return pixcel.SampleDuration
+	case "distinct":
+		return pixcel.SampleDistinct
+	case "fps":
+		return pixcel.SampleFPS
+	default:
+		return pixcel.SampleUniform
+	}
+}
+
+// parseGravity maps a CLI flag string to a [pixcel.Gravity] anchor.
+func parseGravity(name string) pixcel.Gravity {
+	switch strings.ToLower(name) {
+	case "north":
+		return pixcel.GravityNorth
+	case "south":
+		return pixcel.GravitySouth
+	case "east":
+		return pixcel.GravityEast
+	case "west":
+		return pixcel.GravityWest
+	case "northeast":
+		return pixcel.GravityNorthEast
+	case "northwest":
+		return pixcel.GravityNorthWest
+	case "southeast":
+		return pixcel.GravitySouthEast
+	case "southwest":
+		return pixcel.GravitySouthWest
+	default:
+		return pixcel.GravityCenter
+	}
+}
+
+// parseColor parses "transparent" or a CSS-style hex colour (#rgb, #rrggbb
+// or #rrggbbaa, with or without the leading '#').
+func parseColor(s string) (color.Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "transparent" {
+		return color.Transparent, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("%q is not a hex colour", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a hex colour", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// parseSize parses a "WxH" size, or a single "N" meaning N×N. Both
+// dimensions must be positive.
+func parseSize(s string) (int, int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	ws, hs, found := strings.Cut(s, "x")
+	if !found {
+		hs = ws
+	}
+
+	w, errW := strconv.Atoi(ws)
+	h, errH := strconv.Atoi(hs)
+	if errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("%q is not a size (expected WxH or N)", s)
+	}
+	return w, h, nil
+}
+
+// parseCrop parses an "x,y,w,h" crop region. An empty string means no crop.
+func parseCrop(s string) (image.Rectangle, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return image.Rectangle{}, nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return image.Rectangle{}, fmt.Errorf("%q is not a region (expected x,y,w,h)", s)
+	}
+
+	var v [4]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return image.Rectangle{}, fmt.Errorf("%q is not a region (expected x,y,w,h)", s)
+		}
+		v[i] = n
+	}
+	if v[0] < 0 || v[1] < 0 || v[2] <= 0 || v[3] <= 0 {
+		return image.Rectangle{}, fmt.Errorf("%q must have a non-negative origin and positive size", s)
+	}
+	return image.Rect(v[0], v[1], v[0]+v[2], v[1]+v[3]), nil
+}
+
+// parsePoster parses a --reduced-motion poster frame: "first", "last" or a
+// frame index. An empty string disables the reduced-motion poster.
+func parsePoster(s string) (int, bool, error) {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "":
+		return pixcel.PosterFirst, false, nil
+	case "first":
+		return pixcel.PosterFirst, true, nil
+	case "last":
+		return pixcel.PosterLast, true, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, false, fmt.Errorf("%q is not a poster frame (expected first, last or a frame index)", s)
+	}
+	return n, true, nil
+}
+
+// parseTemplate parses a custom page template file with the SDK's template
+// functions. An empty path means the built-in templates.
+func parseTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	t, err := template.New(filepath.Base(path)).Funcs(pixcel.TemplateFuncs()).ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if t.Lookup(pixcel.TemplateImage) == nil && t.Lookup(pixcel.TemplateAnimation) == nil {
+		return nil, fmt.Errorf("%s defines neither %q nor %q", path, pixcel.TemplateImage, pixcel.TemplateAnimation)
+	}
+	return t, nil
+}
+
+// parseTheme builds a [pixcel.Theme] from the --theme preset and the flags
+// overriding its individual values.
+func parseTheme() (pixcel.Theme, error) {
+	var t pixcel.Theme
+	switch strings.ToLower(flagTheme) {
+	case "dark":
+		t = pixcel.ThemeDark
+	case "light":
+		t = pixcel.ThemeLight
+	case "transparent":
+		t = pixcel.ThemeTransparent
+	default:
+		return pixcel.Theme{}, fmt.Errorf("invalid --theme %q (expected dark, light or transparent)", flagTheme)
+	}
+
+	for _, f := range []struct {
+		name, value string
+		dst         *string
+	}{
+		{"background", flagBackground, &t.Background},
+		{"surface", flagSurface, &t.Surface},
+		{"text-color", flagTextColor, &t.Text},
+		{"shadow", flagShadow, &t.Shadow},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if strings.ContainsAny(f.value, ";{}<>\\\n\r") {
+			return pixcel.Theme{}, fmt.Errorf("invalid --%s %q (not a CSS value)", f.name, f.value)
+		}
+		*f.dst = f.value
+	}
+
+	if flagPadding < -1 {
+		return pixcel.Theme{}, fmt.Errorf("invalid --padding %d (must be -1 or more)", flagPadding)
+	}
+	if flagPadding >= 0 {
+		t.Padding = flagPadding
+	}
+	if flagRadius < -1 {
+		return pixcel.Theme{}, fmt.Errorf("invalid --radius %d (must be -1 or more)", flagRadius)
+	}
+	if flagRadius >= 0 {
+		t.Radius = flagRadius
+	}
+
+	switch strings.ToLower(flagAlign) {
+	case "":
+	case "center", "centre":
+		t.Centered = true
+	case "top-left":
+		t.Centered = false
+	default:
+		return pixcel.Theme{}, fmt.Errorf("invalid --align %q (expected center or top-left)", flagAlign)
+	}
+	return t, nil
+}
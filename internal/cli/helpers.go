@@ -6,17 +6,24 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"image"
 	"image/gif"
 	_ "image/gif"  // register GIF decoder
 	_ "image/jpeg" // register JPEG decoder
 	_ "image/png"  // register PNG decoder
+	"io"
 	"os"
+
+	_ "golang.org/x/image/bmp"  // register BMP decoder
+	_ "golang.org/x/image/tiff" // register TIFF decoder
+	_ "golang.org/x/image/webp" // register WebP decoder
 )
 
 // loadImage opens and decodes an image file, returning the decoded image
-// and its format name (e.g. "png", "jpeg", "gif").
+// and its format name (e.g. "png", "jpeg", "gif", "bmp", "tiff", "webp").
 func loadImage(path string) (image.Image, string, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -48,3 +55,51 @@ func loadGIF(path string) (*gif.GIF, error) {
 
 	return g, nil
 }
+
+// isWebP reports whether the file at path starts with a RIFF WEBP header.
+func isWebP(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	var header [12]byte
+	if _, err := io.ReadFull(f, header[:]); err != nil {
+		return false
+	}
+	return bytes.Equal(header[0:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP"))
+}
+
+// isAPNG reports whether the file at path is a PNG with an acTL chunk before
+// its image data, i.e. an animated PNG.
+func isAPNG(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	var sig [8]byte
+	if _, err := io.ReadFull(f, sig[:]); err != nil || string(sig[:]) != "\x89PNG\r\n\x1a\n" {
+		return false
+	}
+
+	var header [8]byte // chunk length and type
+	for {
+		if _, err := io.ReadFull(f, header[:]); err != nil {
+			return false
+		}
+		switch string(header[4:]) {
+		case "acTL":
+			return true
+		case "IDAT", "IEND":
+			return false
+		}
+		// Skip the chunk data and CRC.
+		n := int64(binary.BigEndian.Uint32(header[:4])) + 4
+		if _, err := f.Seek(n, io.SeekCurrent); err != nil {
+			return false
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/H0llyW00dzZ/pixcel/src/pixcel"
+)
+
+// parseCompress parses the --compress flag: a comma-separated list of
+// encodings, "gzip" and/or "br".
+func parseCompress(values []string) ([]pixcel.Encoding, error) {
+	var encs []pixcel.Encoding
+	for _, v := range values {
+		enc := pixcel.Encoding(strings.ToLower(strings.TrimSpace(v)))
+		if enc.Ext() == "" {
+			return nil, fmt.Errorf("unknown encoding %q (want gzip or br)", v)
+		}
+		if !slices.Contains(encs, enc) {
+			encs = append(encs, enc)
+		}
+	}
+	return encs, nil
+}
+
+// outputFile writes converted HTML to a file and, with --compress, to
+// pre-compressed copies next to it (e.g. art.html.gz, art.html.br).
+type outputFile struct {
+	io.Writer
+	paths  []string // the HTML file first, then its compressed copies
+	files  []*os.File
+	encs   []io.WriteCloser
+	closed bool
+}
+
+// createOutput creates the output file at path and its compressed copies.
+func createOutput(path string) (*outputFile, error) {
+	encs, err := parseCompress(flagCompress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --compress: %w", err)
+	}
+
+	o := &outputFile{paths: []string{path}}
+	for _, enc := range encs {
+		o.paths = append(o.paths, path+enc.Ext())
+	}
+
+	writers := make([]io.Writer, 0, len(o.paths))
+	for i, p := range o.paths {
+		f, err := os.Create(p)
+		if err != nil {
+			o.Close()
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		o.files = append(o.files, f)
+		if i == 0 {
+			writers = append(writers, f)
+			continue
+		}
+		cw, err := pixcel.NewCompressWriter(f, encs[i-1])
+		if err != nil {
+			o.Close()
+			return nil, err
+		}
+		o.encs = append(o.encs, cw)
+		writers = append(writers, cw)
+	}
+	o.Writer = io.MultiWriter(writers...)
+	return o, nil
+}
+
+// Close flushes the compressed copies and closes all files. Calling it again
+// does nothing.
+func (o *outputFile) Close() error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+
+	var errs []error
+	for _, cw := range o.encs {
+		errs = append(errs, cw.Close())
+	}
+	for _, f := range o.files {
+		errs = append(errs, f.Close())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// printSizes prints the size of the HTML file and of each compressed copy,
+// as a percentage of the HTML, to compare how well settings compress.
+func (o *outputFile) printSizes() {
+	if len(o.paths) < 2 {
+		return
+	}
+	var size int64
+	for i, p := range o.paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if i == 0 {
+			size = fi.Size()
+			fmt.Printf("  %s: %d bytes\n", p, size)
+			continue
+		}
+		pct := 0.0
+		if size > 0 {
+			pct = float64(fi.Size()) / float64(size) * 100
+		}
+		fmt.Printf("  %s: %d bytes (%.1f%%)\n", p, fi.Size(), pct)
+	}
+}
+
+// printBudget reports the settings --max-bytes settled on.
+func printBudget(b pixcel.Budget) {
+	height := "auto"
+	if b.Height > 0 {
+		height = fmt.Sprint(b.Height)
+	}
+	frames := ""
+	if b.MaxFrames > 0 {
+		frames = fmt.Sprintf(", max frames %d", b.MaxFrames)
+	}
+	fmt.Printf("Fitted %d bytes within --max-bytes %d: width %d, height %s%s, cell %dx%d (%d attempts)\n",
+		b.Bytes, flagMaxBytes, b.Width, height, frames, b.CellWidth, b.CellHeight, b.Attempts)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/H0llyW00dzZ/pixcel/src/pixcel"
+	"github.com/spf13/cobra"
+)
+
+// spritesCmd flag values, scoped to this file.
+var (
+	flagSpritesGrid   string
+	flagSpritesMode   string
+	flagSpritesOutput string
+	flagSpritesDir    string
+	flagSpritesDelay  time.Duration
+)
+
+// spritesCmd slices a spritesheet into tiles and converts each tile.
+var spritesCmd = &cobra.Command{
+	Use:   "sprites <sheet>",
+	Short: renderTemplate("sprites.short"),
+	Long:  renderTemplate("sprites.long"),
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSprites,
+}
+
+func init() {
+	spritesCmd.Flags().StringVar(&flagSpritesGrid, "grid", "auto", "tile size as WxH or N, or auto to detect transparent gutters")
+	spritesCmd.Flags().StringVar(&flagSpritesMode, "mode", "page", "output mode: page (one page with anchors), files (one file per sprite), animate (CSS animation)")
+	spritesCmd.Flags().StringVarP(&flagSpritesOutput, "output", "o", "go_pixel_sprites.html", "output HTML file path for page and animate modes")
+	spritesCmd.Flags().StringVar(&flagSpritesDir, "out-dir", ".", "output directory for files mode")
+	spritesCmd.Flags().DurationVar(&flagSpritesDelay, "delay", 100*time.Millisecond, "time each sprite is shown in animate mode")
+	addConverterFlags(spritesCmd)
+
+	rootCmd.AddCommand(spritesCmd)
+}
+
+// runSprites is the RunE handler for the sprites subcommand.
+func runSprites(cmd *cobra.Command, args []string) error {
+	sheetPath := args[0]
+
+	mode := strings.ToLower(flagSpritesMode)
+	if mode != "page" && mode != "files" && mode != "animate" {
+		return fmt.Errorf("invalid --mode %q (expected page, files or animate)", flagSpritesMode)
+	}
+
+	sheet, format, err := loadImage(sheetPath)
+	if err != nil {
+		return err
+	}
+
+	sprites, err := sliceSheet(sheet)
+	if err != nil {
+		return fmt.Errorf("failed to slice spritesheet: %w", err)
+	}
+	fmt.Printf("Sliced %d sprites from %s image %s\n", len(sprites), format, sheetPath)
+
+	opts, err := converterOptions()
+	if err != nil {
+		return err
+	}
+	// Keep each sprite at its native size unless a size was asked for.
+	if cmd == nil || !cmd.Flags().Changed("width") && !cmd.Flags().Changed("height") {
+		opts = append(opts, pixcel.WithNativeSize(true))
+	}
+	converter := pixcel.New(opts...)
+	ctx := context.Background()
+
+	if mode == "files" {
+		return writeSpriteFiles(ctx, converter, sprites)
+	}
+
+	outFile, err := createOutput(flagSpritesOutput)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	if mode == "animate" {
+		err = converter.ConvertSpriteAnimation(ctx, sprites, flagSpritesDelay, outFile)
+	} else {
+		err = converter.ConvertSprites(ctx, sprites, outFile)
+	}
+	if err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+	if err := outFile.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Done! Saved %d sprites to %s\n", len(sprites), flagSpritesOutput)
+	outFile.printSizes()
+	return nil
+}
+
+// sliceSheet slices the sheet by the --grid flag.
+func sliceSheet(sheet image.Image) ([]pixcel.Sprite, error) {
+	if strings.EqualFold(strings.TrimSpace(flagSpritesGrid), "auto") {
+		return pixcel.SliceAuto(sheet)
+	}
+
+	tileW, tileH, err := parseSize(flagSpritesGrid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --grid: %w", err)
+	}
+	return pixcel.SliceGrid(sheet, tileW, tileH)
+}
+
+// writeSpriteFiles converts each sprite into its own <name>.html file in the
+// --out-dir directory.
+func writeSpriteFiles(ctx context.Context, converter *pixcel.Converter, sprites []pixcel.Sprite) error {
+	if err := os.MkdirAll(flagSpritesDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, s := range sprites {
+		path := filepath.Join(flagSpritesDir, s.Name+".html")
+		if err := writeSpriteFile(ctx, converter, s, path); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Done! Saved %d sprite files to %s\n", len(sprites), flagSpritesDir)
+	return nil
+}
+
+// writeSpriteFile converts a single sprite into the file at path.
+func writeSpriteFile(ctx context.Context, converter *pixcel.Converter, s pixcel.Sprite, path string) error {
+	outFile, err := createOutput(path)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	if err := converter.Convert(ctx, s.Image, outFile); err != nil {
+		return fmt.Errorf("conversion of %s failed: %w", s.Name, err)
+	}
+	return outFile.Close()
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/maphash"
+	"html"
+	"image"
+	"io"
+	"slices"
+	"time"
+)
+
+// defaultFrameDelay is used for frames without a positive delay, matching
+// common browser behaviour for GIFs.
+const defaultFrameDelay = 100 * time.Millisecond
+
+// Animation is a sequence of complete frames played one after another. It
+// is the source-independent input of [Converter.ConvertAnimation]: frames may
+// be generated procedurally, decoded from a GIF, APNG or WebP file, or loaded
+// from a directory of images.
+type Animation struct {
+	// Frames holds the frames in display order. Each frame is rendered as-is,
+	// so any compositing (disposal, blending) must already be applied. All
+	// frames are scaled to the target size derived from the first frame.
+	Frames []image.Image
+
+	// Delays holds how long each frame is shown. A missing or non-positive
+	// delay is treated as 100ms.
+	Delays []time.Duration
+
+	// LoopCount is the number of times the animation plays. Zero loops
+	// forever; after a finite number of plays the last frame stays visible.
+	LoopCount int
+}
+
+// ConvertAnimation writes the animation as HTML pixel art to w. Each frame
+// becomes a separate table layer, animated with pure CSS @keyframes. Frames
+// are cropped according to [WithCrop] and sampled according to
+// [WithMaxFrames]; a single remaining frame is rendered like
+// [Converter.Convert]. Frames that are identical after scaling are rendered
+// once: consecutive duplicates are merged into one longer frame, and repeated
+// frames reuse the same layer, without changing playback timing.
+//
+// ConvertAnimation returns [ErrNilAnimation] if a is nil, [ErrNilWriter] if
+// w is nil, [ErrNoFrames] if a has no frames, and [ErrNilImage] if any frame
+// is nil.
+func (c *Converter) ConvertAnimation(ctx context.Context, a *Animation, w io.Writer) error {
+	if a == nil {
+		return ErrNilAnimation
+	}
+	if w == nil {
+		return ErrNilWriter
+	}
+	if len(a.Frames) == 0 {
+		return ErrNoFrames
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cropped := *a
+	cropped.Frames = make([]image.Image, len(a.Frames))
+	for i, f := range a.Frames {
+		if f == nil {
+			return ErrNilImage
+		}
+		img, err := c.cropImage(f)
+		if err != nil {
+			return err
+		}
+		cropped.Frames[i] = img
+	}
+
+	return c.renderAnimation(ctx, &cropped, w)
+}
+
+// renderAnimation samples, scales, deduplicates and meshes already composited
+// and cropped frames, then renders the animated HTML output via template.
+func (c *Converter) renderAnimation(ctx context.Context, a *Animation, w io.Writer) error {
+	// Email clients cannot animate; render one frame on the static path,
+	// which must not crop it again.
+	if c.profile == ProfileEmail {
+		static := *c
+		static.crop = image.Rectangle{}
+		return static.generateHTML(ctx, c.emailFrame(a), w)
+	}
+
+	if c.maxBytes > 0 {
+		return c.renderWithin(w, len(a.Frames), a.Frames[0].Bounds().Size(), func(c *Converter, w io.Writer) error {
+			return c.renderAnimation(ctx, a, w)
+		})
+	}
+
+	// Sample frames if exceeding maxFrames budget.
+	a = c.sampleFrames(a)
+
+	// Single frame after sampling — delegate to the lighter static path.
+	// The frame is already cropped, so the static path must not crop it again.
+	if len(a.Frames) == 1 {
+		static := *c
+		static.crop = image.Rectangle{}
+		return static.generateHTML(ctx, a.Frames[0], w)
+	}
+
+	// Calculate target dimensions from the first frame.
+	firstBounds := a.Frames[0].Bounds()
+	origW := firstBounds.Dx()
+	origH := firstBounds.Dy()
+
+	if origW == 0 || origH == 0 {
+		return ErrInvalidDimensions
+	}
+
+	targetW, targetH := c.targetSize(origW, origH)
+
+	// Scale every frame, merging consecutive frames that are identical after
+	// scaling into one longer frame.
+	var timeline []*image.RGBA
+	var delays []time.Duration
+	var merged []int // timeline index of every sampled frame
+	var totalDuration float64
+
+	for i, img := range a.Frames {
+		if i%5 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		scaled := c.scaleToSize(img, targetW, targetH)
+		delay := frameDuration(a.Delays, i)
+		totalDuration += delay.Seconds()
+
+		if n := len(timeline); n > 0 && bytes.Equal(timeline[n-1].Pix, scaled.Pix) {
+			delays[n-1] += delay
+			merged = append(merged, n-1)
+			continue
+		}
+		merged = append(merged, len(timeline))
+		timeline = append(timeline, scaled)
+		delays = append(delays, delay)
+	}
+
+	// Every frame is the same picture — render it like a still image.
+	if len(timeline) == 1 {
+		static := *c
+		static.crop = image.Rectangle{}
+		return static.generateHTML(ctx, a.Frames[0], w)
+	}
+
+	// Non-consecutive duplicates share one layer shown in several windows.
+	layers, shown := uniqueFrames(timeline)
+	windows := frameWindows(len(timeline), delays)
+
+	// Build frame data.
+	frames := make([]AnimationFrame, 0, len(layers))
+	baseFrames := slices.Clone(shown[0])
+
+	for i, img := range layers {
+		if c.frameDelta && i > 0 {
+			if delta, ok := deltaImage(layers[0], img); ok {
+				patches, err := buildPatches(ctx, delta, c.obfuscate)
+				if err != nil {
+					return err
+				}
+				frames = append(frames, AnimationFrame{
+					Patches:   patches,
+					Delta:     true,
+					Keyframes: layerKeyframes(windows, shown[i]),
+				})
+				baseFrames = append(baseFrames, shown[i]...)
+				continue
+			}
+		}
+
+		rows, err := c.buildRows(ctx, img)
+		if err != nil {
+			return err
+		}
+		frames = append(frames, AnimationFrame{
+			Rows:      rows,
+			Keyframes: layerKeyframes(windows, shown[i]),
+		})
+	}
+
+	// In delta mode the first frame stays visible underneath every delta frame.
+	slices.Sort(baseFrames)
+	frames[0].Keyframes = layerKeyframes(windows, baseFrames)
+
+	var player []PlayerFrame
+	if c.player && (c.withHTML || c.webComponent) {
+		player = playerTimeline(frames, shown, delays)
+	}
+
+	// The poster frame shown instead of the animation for reduced motion.
+	var poster int
+	if c.reduceMotion {
+		poster = merged[c.posterIndex(len(merged))]
+	}
+	posterLayer := slices.IndexFunc(shown, func(idx []int) bool {
+		return slices.Contains(idx, poster)
+	})
+
+	data := &AnimationTemplateData{
+		WebComponent:     c.webComponent,
+		WithHTML:         c.withHTML,
+		Title:            html.EscapeString(c.htmlTitle),
+		Width:            targetW,
+		Height:           targetH,
+		TotalDurationCSS: fmt.Sprintf("%.3fs", totalDuration),
+		LoopCount:        c.playCount(a.LoopCount),
+		Frames:           frames,
+		Player:           player,
+		ReducedMotion:    c.reduceMotion,
+		Poster:           poster,
+		PosterLayer:      posterLayer,
+		PosterBase:       frames[posterLayer].Delta,
+		CellWidth:        c.cellWidth,
+		CellHeight:       c.cellHeight,
+		SmoothLoad:       c.smoothLoad,
+		Obfuscate:        c.obfuscate,
+		Theme:            c.theme,
+		Prefix:           c.classPrefix(),
+		Responsive:       c.responsive,
+		MinWidth:         c.minWidth,
+		MaxWidth:         c.maxWidth,
+		Unit:             c.sizeUnit(),
+		AltText:          html.EscapeString(c.altText),
+		Description:      html.EscapeString(c.description),
+	}
+
+	return c.execute(w, gifTmpl, TemplateAnimation, data)
+}
+
+// playCount returns the number of plays to render for an animation with the
+// given loop count, applying the [WithLoopCount] override. Zero loops forever.
+func (c *Converter) playCount(loopCount int) int {
+	if c.loopCount >= 0 {
+		return c.loopCount
+	}
+	return max(loopCount, 0)
+}
+
+// posterIndex resolves the configured poster frame for an animation of n
+// frames. [PosterLast] and out-of-range indices select the last frame.
+func (c *Converter) posterIndex(n int) int {
+	if c.posterFrame < 0 || c.posterFrame >= n {
+		return n - 1
+	}
+	return c.posterFrame
+}
+
+// playerTimeline lists, for every frame in playback order, the layer the
+// player script must show and how long to show it.
+func playerTimeline(frames []AnimationFrame, shown [][]int, delays []time.Duration) []PlayerFrame {
+	timeline := make([]PlayerFrame, len(delays))
+	for layer, idx := range shown {
+		for _, i := range idx {
+			timeline[i] = PlayerFrame{
+				Layer: layer,
+				Base:  frames[layer].Delta,
+				Delay: max(delays[i].Milliseconds(), 1),
+			}
+		}
+	}
+	return timeline
+}
+
+// frameDuration returns the delay for frame i, treating a missing or
+// non-positive delay as 100ms.
+func frameDuration(delays []time.Duration, i int) time.Duration {
+	if i < len(delays) && delays[i] > 0 {
+		return delays[i]
+	}
+	return defaultFrameDelay
+}
+
+// uniqueFrames groups identical frames. It returns each distinct frame once,
+// in order of first appearance, together with the indices of the frames it
+// stands for.
+func uniqueFrames(frames []*image.RGBA) ([]*image.RGBA, [][]int) {
+	var (
+		layers []*image.RGBA
+		shown  [][]int
+		seed   = maphash.MakeSeed()
+		byHash = make(map[uint64][]int) // pixel hash → candidate layers
+	)
+
+	for i, f := range frames {
+		h := maphash.Bytes(seed, f.Pix)
+		layer := -1
+		for _, l := range byHash[h] {
+			if bytes.Equal(layers[l].Pix, f.Pix) {
+				layer = l
+				break
+			}
+		}
+		if layer < 0 {
+			layer = len(layers)
+			layers = append(layers, f)
+			shown = append(shown, nil)
+			byHash[h] = append(byHash[h], layer)
+		}
+		shown[layer] = append(shown[layer], i)
+	}
+
+	return layers, shown
+}
+
+// frameWindows returns the start and end of each frame as a percentage of
+// the total animation duration.
+func frameWindows(frameCount int, delays []time.Duration) [][2]float64 {
+	// Calculate total duration.
+	var totalDelay float64
+	for i := range frameCount {
+		totalDelay += frameDuration(delays, i).Seconds()
+	}
+
+	windows := make([][2]float64, frameCount)
+	var cumulative float64
+	for i := range frameCount {
+		delay := frameDuration(delays, i).Seconds()
+		windows[i] = [2]float64{
+			cumulative / totalDelay * 100,
+			(cumulative + delay) / totalDelay * 100,
+		}
+		cumulative += delay
+	}
+
+	return windows
+}
+
+// layerKeyframes generates the @keyframes of a layer that is visible during
+// the given frames, in ascending order. Consecutive frames share a single
+// visible span.
+func layerKeyframes(windows [][2]float64, frames []int) []Keyframe {
+	var keyframes []Keyframe
+
+	// Start hidden if not the very first frame to appear.
+	if windows[frames[0]][0] > 0 {
+		keyframes = append(keyframes, Keyframe{Percent: "0%", Opacity: 0})
+	}
+
+	for i := 0; i < len(frames); {
+		j := i
+		for j+1 < len(frames) && frames[j+1] == frames[j]+1 {
+			j++
+		}
+		onPct := windows[frames[i]][0]
+		offPct := windows[frames[j]][1]
+
+		// Show the layer.
+		keyframes = append(keyframes, Keyframe{Percent: fmt.Sprintf("%.4f%%", onPct), Opacity: 1})
+
+		// Hide the layer when the last frame of the span expires.
+		if offPct < 100 {
+			keyframes = append(keyframes, Keyframe{Percent: fmt.Sprintf("%.4f%%", offPct), Opacity: 0})
+		}
+		i = j + 1
+	}
+
+	// End: the layer of the last frame stays visible until the loop
+	// restarts; others hide.
+	last := frames[len(frames)-1] == len(windows)-1
+	keyframes = append(keyframes, Keyframe{Percent: "100%", Opacity: boolOpacity(last)})
+
+	return keyframes
+}
+
+// boolOpacity maps visibility to a CSS opacity value.
+func boolOpacity(visible bool) int {
+	if visible {
+		return 1
+	}
+	return 0
+}
+
+// rgbaFrames converts composited RGBA frames to the [Animation] frame type.
+func rgbaFrames(frames []*image.RGBA) []image.Image {
+	out := make([]image.Image, len(frames))
+	for i, f := range frames {
+		out[i] = f
+	}
+	return out
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"time"
+)
+
+// pngSignature is the 8-byte header every PNG file starts with.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// APNG dispose_op and blend_op values from the fcTL chunk.
+const (
+	apngDisposeNone       = 0
+	apngDisposeBackground = 1
+	apngDisposePrevious   = 2
+	apngBlendOver         = 1
+)
+
+// apngAnimation is the decoded content of an animated PNG file.
+type apngAnimation struct {
+	Width, Height int
+	LoopCount     int // 0 loops forever
+	Frames        []rasterFrame
+}
+
+// apngChunk is a raw PNG chunk.
+type apngChunk struct {
+	Type string
+	Data []byte
+}
+
+// apngFrameControl holds the fields of an fcTL chunk.
+type apngFrameControl struct {
+	Bounds  image.Rectangle
+	Delay   time.Duration
+	Dispose byte
+	Blend   byte
+}
+
+// ConvertAPNG decodes a PNG image from r and writes HTML pixel art to w.
+// Animated PNG (APNG) files are composited frame by frame (honouring the
+// dispose and blend operations) and rendered with the same CSS keyframe
+// animation as [Converter.ConvertAnimation], including frame sampling via
+// [WithMaxFrames]. The number of plays stored in the file is honoured. PNG
+// files without an acTL chunk are rendered like [Converter.Convert].
+//
+// ConvertAPNG returns [ErrNilReader] if r is nil, [ErrNilWriter] if w is nil,
+// [ErrInvalidAPNG] if the data is not a valid PNG or APNG file, and
+// [ErrNoFrames] if an animated file contains no frames.
+func (c *Converter) ConvertAPNG(ctx context.Context, r io.Reader, w io.Writer) error {
+	if r == nil {
+		return ErrNilReader
+	}
+	if w == nil {
+		return ErrNilWriter
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	anim, err := decodeAPNGAnimation(data)
+	if err != nil {
+		return err
+	}
+	if anim == nil {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return ErrInvalidAPNG
+		}
+		return c.Convert(ctx, img, w)
+	}
+	if len(anim.Frames) == 0 {
+		return ErrNoFrames
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	composited, err := c.compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+	if err != nil {
+		return err
+	}
+
+	return c.renderAnimation(ctx, &Animation{
+		Frames:    rgbaFrames(composited),
+		Delays:    rasterDelays(anim.Frames),
+		LoopCount: anim.LoopCount,
+	}, w)
+}
+
+// decodeAPNGAnimation parses the chunks of a PNG file. It returns a nil
+// animation and no error for PNG files without an acTL chunk, which the
+// standard decoder handles directly.
+func decodeAPNGAnimation(data []byte) (*apngAnimation, error) {
+	chunks, err := readPNGChunks(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 || chunks[0].Type != "IHDR" || len(chunks[0].Data) != 13 {
+		return nil, ErrInvalidAPNG
+	}
+	ihdr := chunks[0].Data
+
+	var (
+		animated bool
+		shared   []apngChunk // PLTE, tRNS and other chunks before IDAT, copied into every frame
+		anim     = &apngAnimation{
+			Width:  int(binary.BigEndian.Uint32(ihdr[0:4])),
+			Height: int(binary.BigEndian.Uint32(ihdr[4:8])),
+		}
+		fc   *apngFrameControl
+		idat [][]byte
+	)
+	canvas := image.Rect(0, 0, anim.Width, anim.Height)
+
+	// flush decodes the pending frame, if any.
+	flush := func() error {
+		if fc == nil {
+			return nil
+		}
+		if len(idat) == 0 {
+			return ErrInvalidAPNG
+		}
+		img, err := decodeAPNGFrame(ihdr, fc.Bounds, shared, idat)
+		if err != nil {
+			return err
+		}
+		frame := rasterFrame{
+			Image:  img,
+			Bounds: fc.Bounds,
+			Delay:  fc.Delay,
+			Blend:  fc.Blend == apngBlendOver,
+		}
+		switch fc.Dispose {
+		case apngDisposeBackground:
+			frame.Dispose = disposeBackground
+		case apngDisposePrevious:
+			frame.Dispose = disposePrevious
+			// A first frame cannot restore a previous state.
+			if len(anim.Frames) == 0 {
+				frame.Dispose = disposeBackground
+			}
+		}
+		anim.Frames = append(anim.Frames, frame)
+		fc, idat = nil, nil
+		return nil
+	}
+
+	seenIDAT := false
+	for _, ch := range chunks[1:] {
+		switch ch.Type {
+		case "acTL":
+			if len(ch.Data) != 8 {
+				return nil, ErrInvalidAPNG
+			}
+			animated = true
+			anim.LoopCount = int(binary.BigEndian.Uint32(ch.Data[4:8]))
+		case "fcTL":
+			// Compositing keeps a canvas-sized snapshot of every frame, so
+			// both the canvas and the frame count are capped before the
+			// frame is decoded.
+			if canvas.Empty() || anim.Width > maxCanvasPixels/anim.Height {
+				return nil, ErrInvalidAPNG
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			if len(anim.Frames) >= maxAnimationPixels/(anim.Width*anim.Height) {
+				return nil, ErrInvalidAPNG
+			}
+			next, err := parseFrameControl(ch.Data)
+			if err != nil {
+				return nil, err
+			}
+			if !next.Bounds.In(canvas) {
+				return nil, ErrInvalidAPNG
+			}
+			fc = &next
+		case "IDAT":
+			// The default image is only part of the animation when an fcTL
+			// chunk precedes it.
+			seenIDAT = true
+			if fc != nil {
+				idat = append(idat, ch.Data)
+			}
+		case "fdAT":
+			if fc == nil || len(ch.Data) < 4 {
+				return nil, ErrInvalidAPNG
+			}
+			idat = append(idat, ch.Data[4:])
+		case "IEND":
+		default:
+			if !seenIDAT {
+				shared = append(shared, ch)
+			}
+		}
+	}
+
+	if !animated {
+		return nil, nil
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return anim, nil
+}
+
+// parseFrameControl decodes the payload of an fcTL chunk.
+func parseFrameControl(data []byte) (apngFrameControl, error) {
+	if len(data) != 26 {
+		return apngFrameControl{}, ErrInvalidAPNG
+	}
+
+	w := int(binary.BigEndian.Uint32(data[4:8]))
+	h := int(binary.BigEndian.Uint32(data[8:12]))
+	x := int(binary.BigEndian.Uint32(data[12:16]))
+	y := int(binary.BigEndian.Uint32(data[16:20]))
+	if w <= 0 || h <= 0 || x < 0 || y < 0 {
+		return apngFrameControl{}, ErrInvalidAPNG
+	}
+
+	num := time.Duration(binary.BigEndian.Uint16(data[20:22]))
+	den := time.Duration(binary.BigEndian.Uint16(data[22:24]))
+	if den == 0 {
+		den = 100 // a zero denominator means hundredths of a second
+	}
+
+	return apngFrameControl{
+		Bounds:  image.Rect(x, y, x+w, y+h),
+		Delay:   num * time.Second / den,
+		Dispose: data[24],
+		Blend:   data[25],
+	}, nil
+}
+
+// decodeAPNGFrame decodes a single frame by rewrapping its image data as a
+// standalone PNG with the frame's dimensions, so the standard decoder can
+// handle it.
+func decodeAPNGFrame(ihdr []byte, bounds image.Rectangle, shared []apngChunk, idat [][]byte) (image.Image, error) {
+	header := bytes.Clone(ihdr)
+	binary.BigEndian.PutUint32(header[0:4], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(header[4:8], uint32(bounds.Dy()))
+
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	writePNGChunk(&buf, "IHDR", header)
+	for _, ch := range shared {
+		writePNGChunk(&buf, ch.Type, ch.Data)
+	}
+	for _, d := range idat {
+		writePNGChunk(&buf, "IDAT", d)
+	}
+	writePNGChunk(&buf, "IEND", nil)
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		return nil, ErrInvalidAPNG
+	}
+	return img, nil
+}
+
+// readPNGChunks splits a PNG file into its chunks, stopping at IEND, and
+// verifies the CRC of each chunk. Frames are decoded from chunks rewrapped
+// with fresh CRCs (see [decodeAPNGFrame]), so corruption must be caught here.
+func readPNGChunks(data []byte) ([]apngChunk, error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, ErrInvalidAPNG
+	}
+	data = data[len(pngSignature):]
+
+	var chunks []apngChunk
+	for len(data) > 0 {
+		if len(data) < 12 {
+			return nil, ErrInvalidAPNG
+		}
+		n := binary.BigEndian.Uint32(data[0:4])
+		if uint64(n) > uint64(len(data)-12) {
+			return nil, ErrInvalidAPNG
+		}
+		ch := apngChunk{Type: string(data[4:8]), Data: data[8 : 8+n]}
+		if crc32.ChecksumIEEE(data[4:8+n]) != binary.BigEndian.Uint32(data[8+n:12+n]) {
+			return nil, ErrInvalidAPNG
+		}
+		chunks = append(chunks, ch)
+		data = data[12+n:]
+		if ch.Type == "IEND" {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+// writePNGChunk writes a PNG chunk with its length and CRC to buf.
+func writePNGChunk(buf *bytes.Buffer, typ string, data []byte) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(data)))
+	buf.Write(n[:])
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	buf.WriteString(typ)
+	buf.Write(data)
+
+	binary.BigEndian.PutUint32(n[:], crc.Sum32())
+	buf.Write(n[:])
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math"
+)
+
+// budgetMargin is the share of the byte budget each retry aims for, so that
+// the next attempt usually fits instead of landing just above the limit.
+const budgetMargin = 0.95
+
+// Budget reports the settings chosen to fit the output within the byte budget
+// of [WithMaxBytes].
+type Budget struct {
+	Width      int // target width in cells
+	Height     int // target height in cells, 0 when proportional to Width
+	MaxFrames  int // animation frame limit (see [WithMaxFrames]), 0 for stills
+	CellWidth  int // cell width in CSS pixels (see [WithCellSize])
+	CellHeight int // cell height in CSS pixels
+	Bytes      int // size of the written output
+	Attempts   int // number of renders it took
+}
+
+// MaxBytesError is returned when the output cannot be made to fit the byte
+// budget of [WithMaxBytes]. It matches [ErrMaxBytes] with errors.Is.
+type MaxBytesError struct {
+	Budget Budget // the smallest settings tried and the size of their output
+	Limit  int    // the byte budget
+}
+
+func (e *MaxBytesError) Error() string {
+	return fmt.Sprintf("%v: smallest output is %d bytes, budget is %d", ErrMaxBytes, e.Budget.Bytes, e.Limit)
+}
+
+// Unwrap returns [ErrMaxBytes].
+func (e *MaxBytesError) Unwrap() error {
+	return ErrMaxBytes
+}
+
+// renderWithin renders with render, which draws the output of one conversion
+// with the settings of the Converter it is given. Without a byte budget it
+// renders straight to w. Otherwise it renders into memory and, while the
+// output is too large, lowers the target size, for animations of frames
+// frames the frame limit, and finally the cell size, in proportion to the
+// excess until the output fits. native is the size of the art, which
+// [WithNativeSize] keeps until it has to shrink. It fails with a
+// [*MaxBytesError] once nothing can be lowered any further.
+func (c *Converter) renderWithin(w io.Writer, frames int, native image.Point, render func(*Converter, io.Writer) error) error {
+	if c.maxBytes <= 0 {
+		return render(c, w)
+	}
+
+	try := *c
+	try.maxBytes = 0
+	if try.nativeSize {
+		try.nativeSize = false
+		try.targetWidth, try.targetHeight = native.X, native.Y
+	}
+	if frames > 1 && (try.maxFrames <= 0 || try.maxFrames > frames) {
+		try.maxFrames = frames
+	}
+
+	var buf bytes.Buffer
+	for attempt := 1; ; attempt++ {
+		buf.Reset()
+		if err := render(&try, &buf); err != nil {
+			return err
+		}
+		b := Budget{
+			Width:      try.targetWidth,
+			Height:     try.targetHeight,
+			CellWidth:  try.cellWidth,
+			CellHeight: try.cellHeight,
+			Bytes:      buf.Len(),
+			Attempts:   attempt,
+		}
+		if frames > 1 {
+			b.MaxFrames = try.maxFrames
+		}
+		if buf.Len() <= c.maxBytes {
+			if c.budgetReport != nil {
+				c.budgetReport(b)
+			}
+			_, err := w.Write(buf.Bytes())
+			return err
+		}
+		if !try.shrink(frames > 1, float64(c.maxBytes)*budgetMargin/float64(buf.Len())) {
+			return &MaxBytesError{Budget: b, Limit: c.maxBytes}
+		}
+	}
+}
+
+// shrink scales the settings of c so that the output shrinks by about factor,
+// always lowering at least one of them, and reports false when the target is
+// one cell wide and high, animations are down to two frames and cells are one
+// pixel. The number of cells grows with the square of the target size;
+// animations split the reduction between the frame limit and the cell count
+// while both can shrink. Once the target size is at its minimum, the cell size
+// is halved instead.
+func (c *Converter) shrink(animated bool, factor float64) bool {
+	canFrames := animated && c.maxFrames > 2
+	canSize := c.targetWidth > 1 || c.targetHeight > 1
+	canCells := c.cellWidth > 1 || c.cellHeight > 1
+	if !canFrames && !canSize && !canCells {
+		return false
+	}
+
+	area := factor
+	if canFrames {
+		share := factor
+		if canSize {
+			share = math.Sqrt(factor)
+		}
+		n := min(c.maxFrames-1, max(2, int(float64(c.maxFrames)*share)))
+		area = factor * float64(c.maxFrames) / float64(n)
+		c.maxFrames = n
+		if area >= 1 {
+			return true
+		}
+	}
+
+	switch {
+	case canSize:
+		scale := math.Sqrt(area)
+		c.targetWidth = max(1, min(c.targetWidth-1, int(float64(c.targetWidth)*scale)))
+		if c.targetHeight > 0 {
+			c.targetHeight = max(1, min(c.targetHeight-1, int(float64(c.targetHeight)*scale)))
+		}
+	case canCells:
+		c.cellWidth = max(1, c.cellWidth/2)
+		c.cellHeight = max(1, c.cellHeight/2)
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+)
+
+// disposeOp selects what happens to a frame's region before the next frame
+// is drawn.
+type disposeOp int
+
+const (
+	// disposeNone leaves the frame on the canvas.
+	disposeNone disposeOp = iota
+	// disposeBackground clears the frame's region to transparent.
+	disposeBackground
+	// disposePrevious restores the region to what it was before the frame.
+	disposePrevious
+)
+
+// maxCanvasPixels caps the canvas size of animated WebP and PNG files (64
+// megapixels, e.g. 8192×8192), so a forged header cannot make the decoders
+// allocate gigabytes for a single canvas. Still images are left to the
+// standard decoders.
+const maxCanvasPixels = 1 << 26
+
+// maxAnimationPixels caps the canvas size times the frame count of animated
+// WebP and PNG files (256 megapixels, 1 GiB of RGBA), since compositing keeps
+// a full-canvas snapshot of every frame before [WithMaxFrames] samples them.
+const maxAnimationPixels = 1 << 28
+
+// rasterFrame is a single decoded frame of an animated WebP or APNG, placed
+// on the animation canvas.
+type rasterFrame struct {
+	Image   image.Image
+	Bounds  image.Rectangle // position on the canvas
+	Delay   time.Duration
+	Blend   bool // alpha-blend onto the canvas (false: overwrite)
+	Dispose disposeOp
+}
+
+// compositeRasterFrames renders each frame onto a full-size width×height
+// canvas, handling the blend and dispose operations to produce complete
+// images for each frame. Each snapshot only keeps the region selected by
+// [WithCrop].
+func (c *Converter) compositeRasterFrames(width, height int, frames []rasterFrame) ([]*image.RGBA, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	region, err := c.cropRect(canvas.Bounds())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*image.RGBA, 0, len(frames))
+
+	for _, frame := range frames {
+		var prevState *image.RGBA
+		if frame.Dispose == disposePrevious {
+			prevState = image.NewRGBA(canvas.Bounds())
+			copy(prevState.Pix, canvas.Pix)
+		}
+
+		op := draw.Over
+		if !frame.Blend {
+			op = draw.Src
+		}
+		draw.Draw(canvas, frame.Bounds, frame.Image, frame.Image.Bounds().Min, op)
+
+		snapshot := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+		draw.Draw(snapshot, snapshot.Bounds(), canvas, region.Min, draw.Src)
+		result = append(result, snapshot)
+
+		// Apply this frame's disposal before the next frame is drawn.
+		switch frame.Dispose {
+		case disposeBackground:
+			draw.Draw(canvas, frame.Bounds, image.NewUniform(color.Transparent), image.Point{}, draw.Src)
+		case disposePrevious:
+			copy(canvas.Pix, prevState.Pix)
+		}
+	}
+
+	return result, nil
+}
+
+// rasterDelays returns the delay of each frame.
+func rasterDelays(frames []rasterFrame) []time.Duration {
+	delays := make([]time.Duration, len(frames))
+	for i, f := range frames {
+		delays[i] = f.Delay
+	}
+	return delays
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Encoding is an HTTP content coding for pre-compressed output, as named in
+// the Content-Encoding header.
+type Encoding string
+
+// Supported encodings.
+const (
+	EncodingGzip   Encoding = "gzip"
+	EncodingBrotli Encoding = "br"
+)
+
+// Ext returns the file name extension static servers and CDNs expect for a
+// pre-compressed copy of a file: ".gz" or ".br". It is empty for unsupported
+// encodings.
+func (e Encoding) Ext() string {
+	switch e {
+	case EncodingGzip:
+		return ".gz"
+	case EncodingBrotli:
+		return ".br"
+	}
+	return ""
+}
+
+// NewCompressWriter returns a writer that compresses everything written to
+// it with enc at the best compression level into w, for output that is
+// compressed once and served many times. Pass it to [Converter.Convert] or
+// any other conversion method and Close it afterwards to flush the
+// compressed stream; closing does not close w. To keep the uncompressed
+// HTML as well, combine the writers with io.MultiWriter.
+func NewCompressWriter(w io.Writer, enc Encoding) (io.WriteCloser, error) {
+	if w == nil {
+		return nil, ErrNilWriter
+	}
+	switch enc {
+	case EncodingGzip:
+		return gzip.NewWriterLevel(w, gzip.BestCompression)
+	case EncodingBrotli:
+		return brotli.NewWriterLevel(w, brotli.BestCompression), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidEncoding, enc)
+}
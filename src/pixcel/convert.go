@@ -11,9 +11,6 @@ import (
 	"html"
 	"image"
 	"io"
-	"math"
-
-	"golang.org/x/image/draw"
 )
 
 // Cell represents a single `<td>` block with a color, column span, and row span.
@@ -23,65 +20,63 @@ type Cell struct {
 	Rowspan int
 }
 
-// templateData holds the dynamic data injected into the HTML template.
-type templateData struct {
-	WithHTML   bool
-	Title      string
-	Width      int
-	Height     int
-	Rows       [][]Cell
-	SmoothLoad bool
-	Obfuscate  bool
-}
-
 // generateHTML contains the core logic for scaling the image and building
 // the optimized HTML table output via templates.
 func (c *Converter) generateHTML(ctx context.Context, img image.Image, w io.Writer) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
+	if c.maxBytes > 0 {
+		r, err := c.cropRect(img.Bounds())
+		if err != nil {
+			return err
+		}
+		return c.renderWithin(w, 1, r.Size(), func(c *Converter, w io.Writer) error {
+			return c.generateHTML(ctx, img, w)
+		})
+	}
 
 	destImg, err := c.scaleImage(img)
 	if err != nil {
 		return err
 	}
 
+	if c.profile == ProfileEmail {
+		return c.generateEmailHTML(ctx, destImg, w)
+	}
+
 	data, err := c.buildTemplateData(ctx, destImg)
 	if err != nil {
 		return err
 	}
 
-	return tmpl.Execute(w, data)
+	return c.execute(w, tmpl, TemplateImage, data)
 }
 
-// scaleImage scales the provided image to the converter's target dimensions.
-// If targetHeight is set, it uses that value directly; otherwise it calculates
-// height proportionally from targetWidth.
+// scaleImage crops the provided image to the configured region, then scales
+// it to the converter's target dimensions. If targetHeight is set, the image
+// is fitted into the target box according to the fit mode; otherwise height
+// is calculated proportionally from targetWidth.
 func (c *Converter) scaleImage(img image.Image) (image.Image, error) {
-	bounds := img.Bounds()
-	origW := bounds.Dx()
-	origH := bounds.Dy()
-
-	if origW == 0 || origH == 0 {
+	if b := img.Bounds(); b.Dx() == 0 || b.Dy() == 0 {
 		return nil, ErrInvalidDimensions
 	}
 
-	targetW := c.targetWidth
-	targetH := c.targetHeight
-	if targetH == 0 {
-		targetH = int(math.Round(float64(origH) * float64(targetW) / float64(origW)))
-		if targetH == 0 {
-			targetH = 1
-		}
+	img, err := c.cropImage(img)
+	if err != nil {
+		return nil, err
 	}
 
-	destImg := image.NewRGBA(image.Rect(0, 0, targetW, targetH))
-	c.scaler.Scale(destImg, destImg.Bounds(), img, bounds, draw.Over, nil)
-	return destImg, nil
+	bounds := img.Bounds()
+	origW := bounds.Dx()
+	origH := bounds.Dy()
+
+	targetW, targetH := c.targetSize(origW, origH)
+	return c.scaleToSize(img, targetW, targetH), nil
 }
 
 // buildTemplateData constructs the data needed for the HTML template, computing 2D colspan/rowspan packing.
-func (c *Converter) buildTemplateData(ctx context.Context, img image.Image) (*templateData, error) {
+func (c *Converter) buildTemplateData(ctx context.Context, img image.Image) (*TemplateData, error) {
 	bounds := img.Bounds()
 	targetW := bounds.Max.X
 	targetH := bounds.Max.Y
@@ -91,14 +86,25 @@ func (c *Converter) buildTemplateData(ctx context.Context, img image.Image) (*te
 		return nil, err
 	}
 
-	return &templateData{
-		WithHTML:   c.withHTML,
-		Title:      html.EscapeString(c.htmlTitle),
-		Width:      targetW,
-		Height:     targetH,
-		Rows:       rows,
-		SmoothLoad: c.smoothLoad,
-		Obfuscate:  c.obfuscate,
+	return &TemplateData{
+		WithHTML:     c.withHTML,
+		WebComponent: c.webComponent,
+		Title:        html.EscapeString(c.htmlTitle),
+		Width:        targetW,
+		Height:       targetH,
+		Rows:         rows,
+		CellWidth:    c.cellWidth,
+		CellHeight:   c.cellHeight,
+		SmoothLoad:   c.smoothLoad,
+		Obfuscate:    c.obfuscate,
+		Theme:        c.theme,
+		Prefix:       c.classPrefix(),
+		Responsive:   c.responsive,
+		MinWidth:     c.minWidth,
+		MaxWidth:     c.maxWidth,
+		Unit:         c.sizeUnit(),
+		AltText:      html.EscapeString(c.altText),
+		Description:  html.EscapeString(c.description),
 	}, nil
 }
 
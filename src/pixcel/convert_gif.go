@@ -8,45 +8,24 @@ package pixcel
 import (
 	"context"
 	_ "embed" // required for go:embed directive
-	"fmt"
-	"html"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/gif"
 	"io"
-	"math"
+	"time"
 
 	xdraw "golang.org/x/image/draw"
 )
 
-// gifFrameData holds the rendered rows and animation keyframes for a single GIF frame.
-type gifFrameData struct {
-	Rows      [][]Cell
-	Keyframes []gifKeyframe
-}
-
-// gifKeyframe represents a single step in the CSS @keyframes rule.
-type gifKeyframe struct {
-	Percent string
-	Opacity int // 0 or 1 (used for opacity animation)
-}
-
-// gifTemplateData holds all data injected into the animated GIF HTML template.
-type gifTemplateData struct {
-	WithHTML         bool
-	Title            string
-	Width            int
-	Height           int
-	TotalDurationCSS string
-	Frames           []gifFrameData
-	SmoothLoad       bool
-	Obfuscate        bool // now properly set (for future template use if needed)
-}
-
 // ConvertGIF takes an animated GIF and writes animated HTML pixel art to the
 // provided writer. Each frame becomes a separate table layer, animated with
-// pure CSS @keyframes.
+// pure CSS @keyframes. The GIF is composited (handling disposal) into an
+// [Animation] and rendered like [Converter.ConvertAnimation].
+//
+// The GIF loop count is honoured: 0 loops forever, -1 plays once and N plays
+// N+1 times, after which the last frame stays visible. [WithLoopCount]
+// overrides it.
 //
 // ConvertGIF returns [ErrNilGIF] if g is nil, [ErrNilWriter] if w is nil,
 // and [ErrNoFrames] if the GIF contains no frames.
@@ -71,82 +50,22 @@ func (c *Converter) generateGIFHTML(ctx context.Context, g *gif.GIF, w io.Writer
 	}
 
 	// Composite all frames into full images (handling GIF disposal).
-	composited := c.compositeFrames(g)
-
-	// Sample frames if exceeding maxFrames budget.
-	composited, g = c.sampleFrames(composited, g)
-
-	// Single frame after sampling — delegate to the lighter static path.
-	if len(composited) == 1 {
-		return c.generateHTML(ctx, composited[0], w)
-	}
-
-	// Calculate target dimensions from the first frame.
-	firstBounds := composited[0].Bounds()
-	origW := firstBounds.Dx()
-	origH := firstBounds.Dy()
-
-	if origW == 0 || origH == 0 {
-		return ErrInvalidDimensions
-	}
-
-	targetW := c.targetWidth
-	targetH := c.targetHeight
-	if targetH == 0 {
-		targetH = int(math.Round(float64(origH) * float64(targetW) / float64(origW)))
-		if targetH == 0 {
-			targetH = 1
-		}
-	}
-
-	// Build CSS keyframes for all frames.
-	allKeyframes := buildAllKeyframes(len(composited), g)
-
-	// Build frame data.
-	frames := make([]gifFrameData, 0, len(composited))
-	var totalDuration float64
-
-	for i, img := range composited {
-		if i%5 == 0 {
-			if err := ctx.Err(); err != nil {
-				return err
-			}
-		}
-
-		scaled := c.scaleToSize(img, targetW, targetH)
-
-		rows, err := c.buildRows(ctx, scaled)
-		if err != nil {
-			return err
-		}
-
-		delay := gifDelay(g, i)
-
-		frames = append(frames, gifFrameData{
-			Rows:      rows,
-			Keyframes: allKeyframes[i],
-		})
-
-		totalDuration += delay
-	}
-
-	data := &gifTemplateData{
-		WithHTML:         c.withHTML,
-		Title:            html.EscapeString(c.htmlTitle),
-		Width:            targetW,
-		Height:           targetH,
-		TotalDurationCSS: fmt.Sprintf("%.3fs", totalDuration),
-		Frames:           frames,
-		SmoothLoad:       c.smoothLoad,
-		Obfuscate:        c.obfuscate, // fixed
+	composited, err := c.compositeFrames(g)
+	if err != nil {
+		return err
 	}
 
-	return gifTmpl.Execute(w, data)
+	return c.renderAnimation(ctx, &Animation{
+		Frames:    rgbaFrames(composited),
+		Delays:    gifDelays(g),
+		LoopCount: gifPlays(g.LoopCount),
+	}, w)
 }
 
 // compositeFrames renders each GIF frame onto a full-size canvas, handling
-// the GIF disposal method to produce complete images for each frame.
-func (c *Converter) compositeFrames(g *gif.GIF) []*image.RGBA {
+// the GIF disposal method to produce complete images for each frame. Each
+// snapshot only keeps the region selected by [WithCrop].
+func (c *Converter) compositeFrames(g *gif.GIF) ([]*image.RGBA, error) {
 	width, height := g.Config.Width, g.Config.Height
 	if width == 0 || height == 0 {
 		b := g.Image[0].Bounds()
@@ -154,6 +73,10 @@ func (c *Converter) compositeFrames(g *gif.GIF) []*image.RGBA {
 	}
 
 	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	region, err := c.cropRect(canvas.Bounds())
+	if err != nil {
+		return nil, err
+	}
 	result := make([]*image.RGBA, 0, len(g.Image))
 
 	var prevState *image.RGBA // for DisposalPrevious
@@ -173,8 +96,8 @@ func (c *Converter) compositeFrames(g *gif.GIF) []*image.RGBA {
 
 		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
 
-		snapshot := image.NewRGBA(canvas.Bounds())
-		copy(snapshot.Pix, canvas.Pix)
+		snapshot := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+		draw.Draw(snapshot, snapshot.Bounds(), canvas, region.Min, draw.Src)
 		result = append(result, snapshot)
 
 		// Save state for next DisposalPrevious
@@ -186,55 +109,18 @@ func (c *Converter) compositeFrames(g *gif.GIF) []*image.RGBA {
 		}
 	}
 
-	return result
+	return result, nil
 }
 
-// sampleFrames reduces composited frames to fit within the maxFrames budget.
-// It always preserves the first and last frames, sampling the middle frames
-// uniformly. The returned GIF is a shallow copy with only the sampled Delay
-// and Disposal entries, so keyframe timing stays correct.
-func (c *Converter) sampleFrames(frames []*image.RGBA, g *gif.GIF) ([]*image.RGBA, *gif.GIF) {
-	n := len(frames)
-	if c.maxFrames <= 0 || n <= c.maxFrames {
-		return frames, g
-	}
-
-	// Build sampled indices: always include first and last.
-	indices := make([]int, 0, c.maxFrames)
-	indices = append(indices, 0)
-	for i := 1; i < c.maxFrames-1; i++ {
-		idx := i * (n - 1) / (c.maxFrames - 1)
-		indices = append(indices, idx)
-	}
-	indices = append(indices, n-1)
-
-	sampled := make([]*image.RGBA, len(indices))
-	sampledDelay := make([]int, len(indices))
-	sampledDisposal := make([]byte, len(indices))
-	for i, idx := range indices {
-		sampled[i] = frames[idx]
-		if idx < len(g.Delay) {
-			sampledDelay[i] = g.Delay[idx]
-		}
-		if idx < len(g.Disposal) {
-			sampledDisposal[i] = g.Disposal[idx]
-		}
-	}
-
-	// Shallow copy the GIF with sampled slices.
-	sg := *g
-	sg.Delay = sampledDelay
-	sg.Disposal = sampledDisposal
-	// Clear Image slice — we don't use it after compositing.
-	sg.Image = nil
-
-	return sampled, &sg
-}
-
-// scaleToSize scales an image to the given target dimensions.
+// scaleToSize scales an image onto a canvas of the given target dimensions,
+// cropping or padding it according to the fit mode and gravity.
 func (c *Converter) scaleToSize(img image.Image, targetW, targetH int) *image.RGBA {
 	destImg := image.NewRGBA(image.Rect(0, 0, targetW, targetH))
-	c.scaler.Scale(destImg, destImg.Bounds(), img, img.Bounds(), xdraw.Over, nil)
+	dr, sr := c.placement(img.Bounds(), targetW, targetH)
+	if c.fit == FitPad && c.targetHeight > 0 {
+		draw.Draw(destImg, destImg.Bounds(), image.NewUniform(c.padColor), image.Point{}, draw.Src)
+	}
+	c.scaler.Scale(destImg, dr, img, sr, xdraw.Over, nil)
 	return destImg
 }
 
@@ -247,61 +133,24 @@ func (c *Converter) buildRows(ctx context.Context, img image.Image) ([][]Cell, e
 	return buildTable(ctx, img, w, h, c.obfuscate)
 }
 
-// gifDelay returns the delay for frame i in seconds.
-// GIF delays are in centiseconds (1/100s). A delay of 0 is treated as 100ms
-// per common browser behavior.
-func gifDelay(g *gif.GIF, i int) float64 {
-	if i < len(g.Delay) && g.Delay[i] > 0 {
-		return float64(g.Delay[i]) / 100.0
+// gifPlays maps a GIF loop count (0 = forever, -1 = no repeat, N = repeat N
+// times) to the number of plays used by [Animation.LoopCount].
+func gifPlays(loopCount int) int {
+	switch {
+	case loopCount == 0:
+		return 0
+	case loopCount < 0:
+		return 1
+	default:
+		return loopCount + 1
 	}
-	return 0.1 // default 100ms
 }
 
-// buildAllKeyframes generates absolute-timed CSS @keyframes for each frame.
-func buildAllKeyframes(frameCount int, g *gif.GIF) [][]gifKeyframe {
-	if frameCount == 0 {
-		return nil
-	}
-
-	// Calculate total duration.
-	var totalDelay float64
-	for i := range frameCount {
-		totalDelay += gifDelay(g, i)
+// gifDelays converts the GIF frame delays (centiseconds) to durations.
+func gifDelays(g *gif.GIF) []time.Duration {
+	delays := make([]time.Duration, len(g.Delay))
+	for i, d := range g.Delay {
+		delays[i] = time.Duration(d) * 10 * time.Millisecond
 	}
-
-	var result [][]gifKeyframe
-	var cumulative float64
-
-	for i := range frameCount {
-		onPct := cumulative / totalDelay * 100
-		delay := gifDelay(g, i)
-		offPct := (cumulative + delay) / totalDelay * 100
-
-		var keyframes []gifKeyframe
-
-		// Start hidden if not the very first frame to appear.
-		if onPct > 0 {
-			keyframes = append(keyframes, gifKeyframe{Percent: "0%", Opacity: 0})
-		}
-
-		// Show frame.
-		keyframes = append(keyframes, gifKeyframe{Percent: fmt.Sprintf("%.4f%%", onPct), Opacity: 1})
-
-		// Hide frame when its delay expires.
-		if offPct < 100 {
-			keyframes = append(keyframes, gifKeyframe{Percent: fmt.Sprintf("%.4f%%", offPct), Opacity: 0})
-		}
-
-		// End: last frame stays visible until the loop restarts; others hide.
-		if i == frameCount-1 {
-			keyframes = append(keyframes, gifKeyframe{Percent: "100%", Opacity: 1})
-		} else {
-			keyframes = append(keyframes, gifKeyframe{Percent: "100%", Opacity: 0})
-		}
-
-		result = append(result, keyframes)
-		cumulative += delay
-	}
-
-	return result
+	return delays
 }
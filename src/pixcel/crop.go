@@ -0,0 +1,53 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"image"
+	"image/draw"
+)
+
+// cropRect resolves the configured crop region against the bounds b. The
+// crop rectangle is relative to the top-left corner of b and is clipped to
+// it. Without a crop region, b is returned unchanged.
+//
+// cropRect returns [ErrInvalidCrop] if the region lies entirely outside b.
+func (c *Converter) cropRect(b image.Rectangle) (image.Rectangle, error) {
+	if c.crop.Empty() {
+		return b, nil
+	}
+	r := c.crop.Add(b.Min).Intersect(b)
+	if r.Empty() {
+		return image.Rectangle{}, ErrInvalidCrop
+	}
+	return r, nil
+}
+
+// cropImage returns the region of img selected by [WithCrop].
+func (c *Converter) cropImage(img image.Image) (image.Image, error) {
+	r, err := c.cropRect(img.Bounds())
+	if err != nil {
+		return nil, err
+	}
+	if r == img.Bounds() {
+		return img, nil
+	}
+	return subImage(img, r), nil
+}
+
+// subImage returns the part of img inside r, which must lie within the image
+// bounds. Images that support SubImage are cropped without copying pixels.
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(r)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"context"
+	"image"
+)
+
+// deltaImage returns an image holding only the pixels of frame that differ
+// from base; unchanged pixels are transparent. It reports false if a changed
+// pixel is not fully opaque while the base pixel is visible, since a patch
+// drawn over the base frame cannot hide what lies underneath.
+func deltaImage(base, frame *image.RGBA) (*image.RGBA, bool) {
+	b := frame.Bounds()
+	delta := image.NewRGBA(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r8, g8, b8, a8 := colorAt(frame, x, y)
+			br, bg, bb, ba := colorAt(base, x, y)
+			if r8 == br && g8 == bg && b8 == bb && a8 == ba {
+				continue
+			}
+			if a8 < 255 && ba > 0 {
+				return nil, false
+			}
+			delta.Set(x, y, frame.At(x, y))
+		}
+	}
+
+	return delta, true
+}
+
+// buildPatches applies the same greedy meshing as [buildTable] to the visible
+// pixels of a delta image, skipping transparent areas entirely.
+func buildPatches(ctx context.Context, img *image.RGBA, obfuscate bool) ([]Patch, error) {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	visited := make([][]bool, height)
+	for i := range visited {
+		visited[i] = make([]bool, width)
+	}
+
+	var patches []Patch
+
+	for y := range height {
+		if y%10 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		for x := range width {
+			if visited[y][x] {
+				continue
+			}
+
+			r8, g8, b8, a8 := colorAt(img, x, y)
+			if a8 == 0 {
+				continue
+			}
+			w := expandWidth(img, visited[y], x, y, width, r8, g8, b8, a8)
+			h := expandHeight(img, x, y, w, height, r8, g8, b8, a8)
+			markVisited(visited, x, y, w, h)
+
+			patches = append(patches, Patch{
+				X:     x,
+				Y:     y,
+				W:     w,
+				H:     h,
+				Color: formatColor(r8, g8, b8, a8, obfuscate),
+			})
+		}
+	}
+
+	return patches, nil
+}
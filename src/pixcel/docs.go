@@ -20,8 +20,78 @@
 //
 //   - [WithTargetWidth] sets the output width in table cells (default: 56).
 //   - [WithTargetHeight] sets the output height in table cells (default: proportional).
+//   - [WithNativeSize] keeps every image at its source size instead (default: off).
 //   - [WithHTMLWrapper] toggles the full HTML document wrapper (default: on).
 //   - [WithSmoothLoad] hides content until fully loaded to prevent progressive rendering (default: off).
 //   - [WithScaler] sets the image scaling algorithm: NearestNeighbor, CatmullRom, BiLinear, ApproxBiLinear (default: NearestNeighbor).
+//   - [WithCrop] converts only a region of the source image, applied before scaling (default: whole image).
+//   - [WithFit] sets how the image fits a fixed width×height box: FitStretch, FitContain, FitCover, FitPad (default: FitStretch).
+//   - [WithGravity] sets the anchor used by FitCover and FitPad (default: GravityCenter).
+//   - [WithPadColor] sets the padding colour used by FitPad (default: transparent).
+//   - [WithCellSize] sets the CSS pixel size of each cell, allowing enlarged or non-square pixels (default: 1×1).
+//   - [WithFrameSampling] chooses how frames beyond [WithMaxFrames] are dropped: SampleUniform, SampleDuration, SampleDistinct, SampleFPS (default: SampleUniform).
+//   - [WithSampleFPS] sets the frame rate used by SampleFPS (default: 10).
+//   - [WithLoopCount] overrides the number of plays of animated output, 0 = forever (default: from the source).
+//   - [WithPlayer] adds a scripted player with play/pause, step, speed and frame slider controls to animations (default: off).
+//   - [WithReducedMotion] shows a poster frame instead of animating for prefers-reduced-motion (default: off).
+//   - [WithFrameDelta] renders later animation frames as only the pixels that changed from the first (default: off).
+//   - [WithWebComponent] wraps the output in a self-registering <pixcel-art> element with Shadow DOM (default: off).
+//   - [WithAltText] marks the art as an image with a text alternative and an optional caption (default: off).
+//   - [WithProfile] with [ProfileEmail] emits email-safe tables without CSS or scripts (default: [ProfileWeb]).
+//   - [WithMaxBytes] shrinks the target size, frame count and cell size until the output fits a byte budget (default: off).
+//   - [WithMinify] removes whitespace, optional quotes and end tags and compacts the embedded CSS (default: off).
+//   - [WithResponsive] scales the art to its container's width within optional bounds (default: off).
+//   - [WithClassPrefix] and [WithUniquePrefix] set the CSS class and @keyframes name prefix (default: "pixcel").
+//   - [WithTheme] sets the page colours, card padding, corner radius, shadow and placement (default: [ThemeDark]).
+//   - [WithTemplate] and [WithTemplateFS] replace the built-in page layout with custom templates (default: built-in).
 //   - [WithObfuscation] randomises inline CSS color formats (hex/rgb/hsl) and property-name casing for bot resistance (default: off; browser use only).
+//
+// # Animation
+//
+// [Converter.ConvertAnimation] renders an [Animation] — complete frames with
+// per-frame delays and a loop count — as stacked tables animated with pure CSS
+// @keyframes, so frames can come from any source:
+//
+//	anim := &pixcel.Animation{
+//	    Frames:    []image.Image{frame1, frame2, frame3},
+//	    Delays:    []time.Duration{80 * time.Millisecond, 80 * time.Millisecond, 200 * time.Millisecond},
+//	    LoopCount: 0, // loop forever
+//	}
+//	err := converter.ConvertAnimation(ctx, anim, os.Stdout)
+//
+// Frames that are identical after scaling are emitted once: consecutive
+// duplicates are merged and repeated frames reuse the same layer.
+//
+// [Converter.ConvertGIF], [Converter.ConvertWebP] and [Converter.ConvertAPNG]
+// are adapters that decode and composite their formats (honouring disposal and
+// blending) into an Animation. ConvertWebP and ConvertAPNG read from an
+// [io.Reader] and fall back to the static path for still images.
+//
+// # Custom templates
+//
+// [WithTemplate] and [WithTemplateFS] plug in a custom text/template layout.
+// The template set defines a [TemplateImage] template, executed with
+// [TemplateData], and a [TemplateAnimation] template, executed with
+// [AnimationTemplateData]. These types form a stable contract that only ever
+// gains fields, and [TemplateFuncs] provides the helpers used by the built-in
+// templates:
+//
+//	page := template.Must(template.New("page").Funcs(pixcel.TemplateFuncs()).Parse(
+//	    `{{define "image"}}<table>{{range .Rows}}<tr>...</tr>{{end}}</table>{{end}}`))
+//	converter := pixcel.New(pixcel.WithTemplate(page))
+//
+// A conversion whose template is missing fails with [ErrInvalidTemplate].
+//
+// # Spritesheets
+//
+// [SliceGrid] cuts a sheet into fixed-size tiles and [SliceAuto] detects tiles
+// by the fully transparent gutters between them. The resulting [Sprite] values
+// can be rendered on one page with named anchors via [Converter.ConvertSprites],
+// or played in order as a CSS animation via [Converter.ConvertSpriteAnimation].
+//
+// # Compression
+//
+// [NewCompressWriter] wraps the writer passed to a conversion to produce
+// pre-compressed output for static hosts and CDNs, using [EncodingGzip] or
+// [EncodingBrotli]; [Encoding.Ext] gives the matching file name extension.
 package pixcel
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"context"
+	"html"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// Profile selects the kind of HTML the converter emits.
+type Profile int
+
+const (
+	// ProfileWeb emits HTML for browsers, styled with CSS and animated with
+	// CSS @keyframes. This is the default.
+	ProfileWeb Profile = iota
+
+	// ProfileEmail emits HTML that email clients render: tables sized with
+	// width and height attributes and coloured with bgcolor, without <style>
+	// blocks, style attributes, CSS classes or scripts. Animations show their
+	// first frame, or the poster frame of [WithReducedMotion].
+	ProfileEmail
+)
+
+// emailAlphaCutoff is the alpha below which a pixel is left transparent in
+// email output; bgcolor cannot express partial transparency.
+const emailAlphaCutoff = 128
+
+// emailFrame picks the single frame an animation is reduced to in email
+// output: the poster frame with [WithReducedMotion], otherwise the first.
+func (c *Converter) emailFrame(a *Animation) image.Image {
+	if !c.reduceMotion {
+		return a.Frames[0]
+	}
+	a = c.sampleFrames(a)
+	return a.Frames[c.posterIndex(len(a.Frames))]
+}
+
+// emailSprite is one sprite of a spritesheet in email output.
+type emailSprite struct {
+	Name string
+	*TemplateData
+}
+
+// emailSpritesData holds the data injected into the "email.sprites" template.
+type emailSpritesData struct {
+	WithHTML bool
+	Title    string
+	Theme    Theme
+	Sprites  []emailSprite
+}
+
+// generateEmailHTML renders an already scaled image with the email template.
+func (c *Converter) generateEmailHTML(ctx context.Context, img image.Image, w io.Writer) error {
+	data, err := c.buildEmailData(ctx, img)
+	if err != nil {
+		return err
+	}
+	return c.render(w, emailTmpl, data)
+}
+
+// generateEmailSprites renders each sprite with the email template, one table
+// per sprite, on a single page.
+func (c *Converter) generateEmailSprites(ctx context.Context, sprites []Sprite, w io.Writer) error {
+	data := &emailSpritesData{
+		WithHTML: c.withHTML,
+		Title:    html.EscapeString(c.htmlTitle),
+		Theme:    c.theme,
+		Sprites:  make([]emailSprite, 0, len(sprites)),
+	}
+	for _, s := range sprites {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if s.Image == nil {
+			return ErrNilImage
+		}
+
+		scaled, err := c.scaleImage(s.Image)
+		if err != nil {
+			return err
+		}
+		art, err := c.buildEmailData(ctx, scaled)
+		if err != nil {
+			return err
+		}
+		art.AltText, art.Description = "", ""
+		data.Sprites = append(data.Sprites, emailSprite{Name: html.EscapeString(s.Name), TemplateData: art})
+	}
+
+	return c.render(w, emailTmpl.Lookup("email.sprites"), data)
+}
+
+// buildEmailData prepares an already scaled image for the email template.
+// Translucent pixels become opaque or transparent, and the cells carry bare
+// hex colours for bgcolor.
+func (c *Converter) buildEmailData(ctx context.Context, img image.Image) (*TemplateData, error) {
+	b := img.Bounds()
+	opaque := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := range b.Dy() {
+		for x := range b.Dx() {
+			r, g, bl, a := colorAt(img, b.Min.X+x, b.Min.Y+y)
+			if a >= emailAlphaCutoff {
+				opaque.SetRGBA(x, y, color.RGBA{R: r, G: g, B: bl, A: 255})
+			}
+		}
+	}
+
+	rows, err := buildTable(ctx, opaque, b.Dx(), b.Dy(), false)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i].Color = strings.TrimPrefix(row[i].Color, "background-color:")
+		}
+	}
+
+	return &TemplateData{
+		WithHTML:    c.withHTML,
+		Title:       html.EscapeString(c.htmlTitle),
+		Width:       b.Dx(),
+		Height:      b.Dy(),
+		Rows:        rows,
+		CellWidth:   c.cellWidth,
+		CellHeight:  c.cellHeight,
+		Theme:       c.theme,
+		Prefix:      c.classPrefix(),
+		Unit:        "px",
+		AltText:     html.EscapeString(c.altText),
+		Description: html.EscapeString(c.description),
+	}, nil
+}
@@ -17,9 +17,50 @@ var (
 	// ErrInvalidDimensions is returned when an image has zero width or height.
 	ErrInvalidDimensions = errors.New("pixcel: invalid image dimensions (zero width or height)")
 
+	// ErrNilReader is returned when a nil reader is passed to ConvertWebP or
+	// ConvertAPNG.
+	ErrNilReader = errors.New("pixcel: reader must not be nil")
+
 	// ErrNilGIF is returned when a nil *gif.GIF is passed to ConvertGIF.
 	ErrNilGIF = errors.New("pixcel: gif must not be nil")
 
-	// ErrNoFrames is returned when a GIF contains zero frames.
-	ErrNoFrames = errors.New("pixcel: gif contains no frames")
+	// ErrNilAnimation is returned when a nil *Animation is passed to
+	// ConvertAnimation.
+	ErrNilAnimation = errors.New("pixcel: animation must not be nil")
+
+	// ErrNoFrames is returned when a GIF or animation contains zero frames.
+	ErrNoFrames = errors.New("pixcel: animation contains no frames")
+
+	// ErrInvalidWebP is returned when ConvertWebP is given data that is not
+	// a valid WebP file.
+	ErrInvalidWebP = errors.New("pixcel: invalid webp data")
+
+	// ErrInvalidAPNG is returned when ConvertAPNG is given data that is not
+	// a valid PNG or APNG file.
+	ErrInvalidAPNG = errors.New("pixcel: invalid png data")
+
+	// ErrInvalidTemplate is returned when a custom template set configured
+	// with WithTemplate or WithTemplateFS cannot be parsed or lacks the
+	// template needed for the conversion.
+	ErrInvalidTemplate = errors.New("pixcel: invalid template")
+
+	// ErrInvalidCrop is returned when the crop region set by WithCrop lies
+	// entirely outside the image.
+	ErrInvalidCrop = errors.New("pixcel: crop region is outside the image")
+
+	// ErrInvalidTileSize is returned when SliceGrid is given a non-positive tile size.
+	ErrInvalidTileSize = errors.New("pixcel: tile width and height must be positive")
+
+	// ErrNoSprites is returned when a spritesheet yields no sprites, or when
+	// an empty sprite list is passed to ConvertSprites or ConvertSpriteAnimation.
+	ErrNoSprites = errors.New("pixcel: no sprites found")
+
+	// ErrInvalidEncoding is returned when NewCompressWriter is given an
+	// encoding other than EncodingGzip or EncodingBrotli.
+	ErrInvalidEncoding = errors.New("pixcel: unsupported compression encoding")
+
+	// ErrMaxBytes is matched by the MaxBytesError returned when the output
+	// cannot be made to fit the byte budget set by WithMaxBytes, even at the
+	// smallest size, frame count and cell size.
+	ErrMaxBytes = errors.New("pixcel: output does not fit the byte budget")
 )
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"image"
+	"math"
+)
+
+// Fit controls how the source image is mapped onto the target box when both
+// [WithTargetWidth] and [WithTargetHeight] are set. When only the width is
+// set, the height is always calculated proportionally and Fit has no effect.
+type Fit int
+
+const (
+	// FitStretch scales the image to exactly the target box, ignoring the
+	// source aspect ratio. This is the default.
+	FitStretch Fit = iota
+
+	// FitContain scales the image to fit inside the target box while
+	// preserving its aspect ratio. The output is the scaled size, so one
+	// dimension may be smaller than requested.
+	FitContain
+
+	// FitCover scales the image to completely cover the target box while
+	// preserving its aspect ratio, cropping the overflow according to the
+	// configured [Gravity].
+	FitCover
+
+	// FitPad behaves like [FitContain] but always outputs the exact target box,
+	// filling the remaining area with the pad colour and positioning the image
+	// according to the configured [Gravity].
+	FitPad
+)
+
+// Gravity selects the anchor used by [FitCover] to choose which part of the
+// image is kept, and by [FitPad] to choose where the image is placed.
+type Gravity int
+
+const (
+	// GravityCenter anchors the image to the centre of the box (default).
+	GravityCenter Gravity = iota
+	// GravityNorth anchors the image to the top edge.
+	GravityNorth
+	// GravitySouth anchors the image to the bottom edge.
+	GravitySouth
+	// GravityEast anchors the image to the right edge.
+	GravityEast
+	// GravityWest anchors the image to the left edge.
+	GravityWest
+	// GravityNorthEast anchors the image to the top-right corner.
+	GravityNorthEast
+	// GravityNorthWest anchors the image to the top-left corner.
+	GravityNorthWest
+	// GravitySouthEast anchors the image to the bottom-right corner.
+	GravitySouthEast
+	// GravitySouthWest anchors the image to the bottom-left corner.
+	GravitySouthWest
+)
+
+// offset distributes the free space around an anchored rectangle, returning
+// the top-left position of the rectangle within the free area.
+func (g Gravity) offset(free image.Point) image.Point {
+	var fx, fy float64 = 0.5, 0.5
+	switch g {
+	case GravityNorth, GravityNorthEast, GravityNorthWest:
+		fy = 0
+	case GravitySouth, GravitySouthEast, GravitySouthWest:
+		fy = 1
+	}
+	switch g {
+	case GravityWest, GravityNorthWest, GravitySouthWest:
+		fx = 0
+	case GravityEast, GravityNorthEast, GravitySouthEast:
+		fx = 1
+	}
+	return image.Pt(
+		int(math.Round(float64(free.X)*fx)),
+		int(math.Round(float64(free.Y)*fy)),
+	)
+}
+
+// targetSize returns the output dimensions for a source of origW×origH,
+// honouring the target width/height and the fit mode.
+func (c *Converter) targetSize(origW, origH int) (int, int) {
+	if c.nativeSize {
+		return origW, origH
+	}
+	targetW := c.targetWidth
+	targetH := c.targetHeight
+	if targetH == 0 {
+		return targetW, proportional(origH, targetW, origW)
+	}
+	if c.fit == FitContain {
+		return containSize(origW, origH, targetW, targetH)
+	}
+	return targetW, targetH
+}
+
+// placement returns the rectangle of the source image to sample and the
+// rectangle of the targetW×targetH canvas to draw it into.
+func (c *Converter) placement(src image.Rectangle, targetW, targetH int) (dr, sr image.Rectangle) {
+	canvas := image.Rect(0, 0, targetW, targetH)
+	if c.targetHeight == 0 || c.nativeSize {
+		return canvas, src
+	}
+
+	switch c.fit {
+	case FitCover:
+		cropW, cropH := containSize(targetW, targetH, src.Dx(), src.Dy())
+		off := c.gravity.offset(image.Pt(src.Dx()-cropW, src.Dy()-cropH))
+		origin := src.Min.Add(off)
+		return canvas, image.Rectangle{Min: origin, Max: origin.Add(image.Pt(cropW, cropH))}
+	case FitPad:
+		w, h := containSize(src.Dx(), src.Dy(), targetW, targetH)
+		off := c.gravity.offset(image.Pt(targetW-w, targetH-h))
+		return image.Rectangle{Min: off, Max: off.Add(image.Pt(w, h))}, src
+	default:
+		return canvas, src
+	}
+}
+
+// containSize returns the largest w×h with the aspect ratio of srcW×srcH that
+// fits inside boxW×boxH. Neither dimension is ever smaller than 1.
+func containSize(srcW, srcH, boxW, boxH int) (int, int) {
+	if srcW*boxH > srcH*boxW {
+		return boxW, proportional(srcH, boxW, srcW)
+	}
+	return proportional(srcW, boxH, srcH), boxH
+}
+
+// proportional returns round(v * num / den), floored at 1.
+func proportional(v, num, den int) int {
+	r := int(math.Round(float64(v) * float64(num) / float64(den)))
+	if r == 0 {
+		return 1
+	}
+	return r
+}
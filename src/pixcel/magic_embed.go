@@ -10,14 +10,48 @@ import (
 	"text/template"
 )
 
+//go:embed template_theme.go.tmpl
+var themeTemplate string
+
+//go:embed template_component.go.tmpl
+var componentTemplate string
+
+//go:embed template_a11y.go.tmpl
+var a11yTemplate string
+
 //go:embed template.go.tmpl
 var pixelArtTemplate string
 
-var tmpl = template.Must(template.New("pixelart").Parse(pixelArtTemplate))
+var tmpl = parsePage("pixelart", pixelArtTemplate)
 
 //go:embed template_gif.go.tmpl
 var gifTemplate string
 
-var gifTmpl = template.Must(template.New("gifart").Funcs(template.FuncMap{
+var gifTmpl = parsePage("gifart", gifTemplate)
+
+//go:embed template_email.go.tmpl
+var emailTemplate string
+
+var emailTmpl = template.Must(template.New("emailart").Funcs(templateFuncs).Parse(emailTemplate))
+
+//go:embed template_sprites.go.tmpl
+var spritesTemplate string
+
+var spritesTmpl = template.Must(template.New("spritesart").Funcs(templateFuncs).Parse(spritesTemplate))
+
+// templateFuncs are the helper functions available to the output templates.
+var templateFuncs = template.FuncMap{
 	"inc": func(i int) int { return i + 1 },
-}).Parse(gifTemplate))
+	"dec": func(i int) int { return i - 1 },
+	"mul": func(a, b int) int { return a * b },
+}
+
+// parsePage parses a page template together with the shared theme, web
+// component and accessibility definitions it uses.
+func parsePage(name, src string) *template.Template {
+	t := template.Must(template.New(name).Funcs(templateFuncs).Parse(src))
+	for _, partial := range []string{themeTemplate, componentTemplate, a11yTemplate} {
+		template.Must(t.Parse(partial))
+	}
+	return t
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// optionalEndTags are the end tags the HTML parser implies on its own in the
+// markup pixcel generates, so minified output can leave them out.
+var optionalEndTags = map[string]bool{
+	"td":     true,
+	"tr":     true,
+	"tbody":  true,
+	"option": true,
+}
+
+// cssComment matches a CSS comment.
+var cssComment = regexp.MustCompile(`/\*[\s\S]*?\*/`)
+
+// minifyHTML shrinks rendered HTML without changing the document it parses
+// to: whitespace-only text between tags is dropped and other text has its
+// whitespace collapsed, attribute values that need no quotes lose them,
+// optional end tags are omitted, and embedded CSS and scripts are compacted.
+// It understands the markup produced by the built-in templates and keeps
+// anything it does not recognise as is.
+func minifyHTML(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src))
+
+	for i := 0; i < len(src); {
+		if src[i] != '<' {
+			j := bytes.IndexByte(src[i:], '<')
+			if j < 0 {
+				j = len(src) - i
+			}
+			if text := src[i : i+j]; len(bytes.TrimSpace(text)) > 0 {
+				out.Write(collapseSpace(text))
+			}
+			i += j
+			continue
+		}
+
+		if bytes.HasPrefix(src[i:], []byte("<!--")) {
+			end := bytes.Index(src[i:], []byte("-->"))
+			if end < 0 {
+				out.Write(src[i:])
+				break
+			}
+			i += end + len("-->")
+			continue
+		}
+
+		end := tagEnd(src[i:])
+		if end < 0 {
+			out.Write(src[i:])
+			break
+		}
+		tag := src[i : i+end+1]
+		i += end + 1
+
+		name, closing := tagName(tag)
+		if closing && optionalEndTags[name] {
+			continue
+		}
+		out.WriteString(minifyTag(tag))
+
+		if closing || (name != "style" && name != "script") {
+			continue
+		}
+		// Raw text runs up to the matching end tag.
+		closeTag := []byte("</" + name)
+		j := bytes.Index(bytes.ToLower(src[i:]), closeTag)
+		if j < 0 {
+			j = len(src) - i
+		}
+		if name == "style" {
+			out.WriteString(minifyCSS(string(src[i : i+j])))
+		} else {
+			out.WriteString(minifyScript(string(src[i : i+j])))
+		}
+		i += j
+	}
+
+	return out.Bytes()
+}
+
+// tagEnd returns the index of the '>' closing the tag at the start of s,
+// skipping quoted attribute values, or -1 if the tag is unterminated.
+func tagEnd(s []byte) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// tagName returns the lower-cased name of a tag and whether it is an end tag.
+func tagName(tag []byte) (string, bool) {
+	s := tag[1:]
+	closing := len(s) > 0 && s[0] == '/'
+	if closing {
+		s = s[1:]
+	}
+	n := 0
+	for n < len(s) && !isSpace(s[n]) && s[n] != '>' && s[n] != '/' {
+		n++
+	}
+	return strings.ToLower(string(s[:n])), closing
+}
+
+// minifyTag rewrites a start or end tag with single spaces between its
+// attributes and without quotes around values that do not need them.
+// Declarations such as <!DOCTYPE html> are returned unchanged.
+func minifyTag(tag []byte) string {
+	if len(tag) > 1 && tag[1] == '!' {
+		return string(tag)
+	}
+
+	s := tag[1 : len(tag)-1]
+	var b strings.Builder
+	b.WriteByte('<')
+
+	// Tag name, including the slash of end tags.
+	n := 0
+	if len(s) > 0 && s[0] == '/' {
+		n++
+	}
+	for n < len(s) && !isSpace(s[n]) && s[n] != '/' {
+		n++
+	}
+	b.Write(s[:n])
+	s = s[n:]
+
+	for {
+		s = bytes.TrimLeft(s, " \t\r\n")
+		if len(s) == 0 {
+			break
+		}
+		if s[0] == '/' {
+			b.WriteByte('/')
+			s = s[1:]
+			continue
+		}
+
+		// Attribute name.
+		n = 0
+		for n < len(s) && !isSpace(s[n]) && s[n] != '=' && s[n] != '/' {
+			n++
+		}
+		b.WriteByte(' ')
+		b.Write(s[:n])
+		s = bytes.TrimLeft(s[n:], " \t\r\n")
+		if len(s) == 0 || s[0] != '=' {
+			continue
+		}
+		s = bytes.TrimLeft(s[1:], " \t\r\n")
+
+		// Attribute value, quoted or not.
+		var value []byte
+		if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+			end := bytes.IndexByte(s[1:], s[0])
+			if end < 0 {
+				end = len(s) - 1
+			}
+			value, s = s[1:1+end], s[min(end+2, len(s)):]
+			if needsQuotes(value) {
+				q := byte('"')
+				if bytes.IndexByte(value, '"') >= 0 {
+					q = '\''
+				}
+				b.WriteByte('=')
+				b.WriteByte(q)
+				b.Write(value)
+				b.WriteByte(q)
+				continue
+			}
+		} else {
+			n = 0
+			for n < len(s) && !isSpace(s[n]) {
+				n++
+			}
+			value, s = s[:n], s[n:]
+		}
+		b.WriteByte('=')
+		b.Write(value)
+	}
+
+	b.WriteByte('>')
+	return b.String()
+}
+
+// needsQuotes reports whether an attribute value must stay quoted.
+func needsQuotes(value []byte) bool {
+	return len(value) == 0 || bytes.ContainsAny(value, " \t\r\n\f\"'=<>`")
+}
+
+// collapseSpace replaces each run of whitespace in text with a single space.
+func collapseSpace(text []byte) []byte {
+	out := make([]byte, 0, len(text))
+	space := false
+	for _, c := range text {
+		if isSpace(c) {
+			space = true
+			continue
+		}
+		if space {
+			out = append(out, ' ')
+			space = false
+		}
+		out = append(out, c)
+	}
+	if space {
+		out = append(out, ' ')
+	}
+	return out
+}
+
+// minifyCSS removes comments and the whitespace CSS does not need around
+// braces, semicolons, commas and after colons, and the last semicolon of
+// each block. Spaces before a colon are kept since they separate selectors
+// from pseudo-classes.
+func minifyCSS(css string) string {
+	css = cssComment.ReplaceAllString(css, "")
+	css = strings.Join(strings.Fields(css), " ")
+	for _, p := range []string{"{", "}", ";", ","} {
+		css = strings.ReplaceAll(css, " "+p, p)
+		css = strings.ReplaceAll(css, p+" ", p)
+	}
+	css = strings.ReplaceAll(css, ": ", ":")
+	return strings.ReplaceAll(css, ";}", "}")
+}
+
+// minifyScript drops indentation and blank lines. Line breaks are kept, so
+// automatic semicolon insertion behaves as in the original.
+func minifyScript(js string) string {
+	lines := strings.Split(js, "\n")
+	kept := lines[:0]
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			kept = append(kept, l)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isSpace reports whether c is HTML whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
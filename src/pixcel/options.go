@@ -5,7 +5,15 @@
 
 package pixcel
 
-import "golang.org/x/image/draw"
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"text/template"
+
+	"golang.org/x/image/draw"
+)
 
 // Option is a functional option for configuring the Converter.
 type Option func(*Converter)
@@ -19,8 +27,20 @@ func WithTargetWidth(w int) Option {
 	}
 }
 
+// WithNativeSize converts every image at its own size, one cell per source
+// pixel (after [WithCrop]), instead of scaling it to [WithTargetWidth] and
+// [WithTargetHeight]. It suits sprites of differing sizes converted by one
+// Converter, e.g. with [Converter.ConvertSprites]. Disabled by default.
+func WithNativeSize(enabled bool) Option {
+	return func(c *Converter) {
+		c.nativeSize = enabled
+	}
+}
+
 // WithHTMLWrapper determines if the output includes the full <html>, <head>, and <body>
-// wrapper around the generated table. If false, only the <table> is output.
+// wrapper around the generated table. If false, only the <table> is output;
+// animations are preceded by a <style> block whose rules are scoped by the
+// class prefix (see [WithClassPrefix]).
 func WithHTMLWrapper(enabled bool, title string) Option {
 	return func(c *Converter) {
 		c.withHTML = enabled
@@ -31,9 +51,9 @@ func WithHTMLWrapper(enabled bool, title string) Option {
 }
 
 // WithTargetHeight configures the output table height in cells (pixels).
-// When set, it overrides the proportional height calculation.
-// If both [WithTargetWidth] and WithTargetHeight are set, the image
-// is stretched to exactly those dimensions.
+// When set, it overrides the proportional height calculation, and the image
+// is fitted into the [WithTargetWidth] × WithTargetHeight box according to
+// [WithFit] (stretched by default).
 func WithTargetHeight(h int) Option {
 	return func(c *Converter) {
 		if h > 0 {
@@ -42,6 +62,20 @@ func WithTargetHeight(h int) Option {
 	}
 }
 
+// WithWebComponent wraps the output in a self-registering <pixcel-art>
+// custom element instead of a page or a bare table. The art and its styles
+// are copied into the element's shadow root, so the snippet can be dropped
+// into any page without its rules leaking into the host's styles, and any
+// number of snippets can share a page. The element is defined once, by
+// whichever snippet loads first. It takes precedence over
+// [WithHTMLWrapper]; [WithSmoothLoad] does not apply, and spritesheet pages
+// rendered by [Converter.ConvertSprites] are unaffected. Disabled by default.
+func WithWebComponent(enabled bool) Option {
+	return func(c *Converter) {
+		c.webComponent = enabled
+	}
+}
+
 // WithSmoothLoad controls whether the generated HTML hides its content until
 // the page is fully loaded, then reveals it with a smooth transition.
 // This prevents the "drawing animation" caused by progressive rendering of
@@ -85,8 +119,9 @@ func WithObfuscation(enabled bool) Option {
 
 // WithMaxFrames sets the maximum number of frames to process when converting
 // an animated GIF. If the GIF contains more frames than this limit, frames
-// are sampled uniformly to stay within the budget while preserving the first
-// and last frames. The default is 10.
+// are sampled to stay within the budget according to [WithFrameSampling];
+// by default uniformly, preserving the first and last frames. The default
+// is 10.
 //
 // This prevents excessively large HTML output and keeps CSS @keyframes
 // animation performant in browsers.
@@ -97,3 +132,295 @@ func WithMaxFrames(n int) Option {
 		}
 	}
 }
+
+// WithFrameSampling selects how animations with more frames than allowed by
+// [WithMaxFrames] are sampled: [SampleUniform] (default), [SampleDuration],
+// [SampleDistinct] or [SampleFPS]. Unknown values are ignored.
+func WithFrameSampling(s FrameSampling) Option {
+	return func(c *Converter) {
+		if s >= SampleUniform && s <= SampleFPS {
+			c.sampling = s
+		}
+	}
+}
+
+// WithSampleFPS sets the frame rate used by [SampleFPS]. The default is 10.
+// Non-positive values are ignored.
+func WithSampleFPS(fps float64) Option {
+	return func(c *Converter) {
+		if fps > 0 {
+			c.sampleFPS = fps
+		}
+	}
+}
+
+// WithLoopCount overrides the number of times animated output plays, for
+// every animation source. Zero loops forever; after a finite number of plays
+// the last frame stays visible. Negative values are ignored. By default the
+// source's loop count is used (see [Converter.ConvertGIF] and [Animation]).
+func WithLoopCount(n int) Option {
+	return func(c *Converter) {
+		if n >= 0 {
+			c.loopCount = n
+		}
+	}
+}
+
+// WithFrameDelta enables delta encoding for animated output. The first frame
+// is rendered as a full table layer, and every later frame contains only the
+// pixels that differ from it, meshed into absolutely positioned rectangles
+// drawn over the first frame. This greatly reduces the output size of sprite
+// animations with a static background. A frame that makes a pixel of the
+// first frame (semi-)transparent cannot be drawn over it and is rendered as a
+// full layer instead. Disabled by default.
+func WithFrameDelta(enabled bool) Option {
+	return func(c *Converter) {
+		c.frameDelta = enabled
+	}
+}
+
+// WithPlayer replaces the CSS-only animation with a small inline script that
+// adds play/pause, step, speed and frame slider controls below the animation.
+// Playback starts paused for visitors who prefer reduced motion. It only
+// affects full-page output (see [WithHTMLWrapper]) and [WithWebComponent].
+// Disabled by default.
+func WithPlayer(enabled bool) Option {
+	return func(c *Converter) {
+		c.player = enabled
+	}
+}
+
+// Poster frame selectors for [WithReducedMotion]. Any other non-negative
+// value is a frame index.
+const (
+	// PosterFirst shows the first frame.
+	PosterFirst = 0
+	// PosterLast shows the last frame.
+	PosterLast = -1
+)
+
+// WithReducedMotion makes animated output respect the prefers-reduced-motion
+// user preference: visitors who request reduced motion see a still poster
+// frame instead of the animation. The poster is [PosterFirst], [PosterLast]
+// or a frame index counted after [WithMaxFrames] sampling; indices past the
+// end select the last frame and other negative values are ignored. With
+// [WithPlayer], playback starts paused on the poster frame. Disabled by
+// default.
+func WithReducedMotion(enabled bool, poster int) Option {
+	return func(c *Converter) {
+		c.reduceMotion = enabled
+		if poster >= PosterLast {
+			c.posterFrame = poster
+		}
+	}
+}
+
+// WithTheme sets the styling of the page wrapped around the art: page and
+// card colours, padding, corner radius, shadow and whether the card is
+// centred. Use [ThemeDark] (default), [ThemeLight] or [ThemeTransparent], or
+// adjust a copy of one. Themes with negative sizes or CSS values containing
+// any of ; { } < > or a backslash are ignored.
+func WithTheme(t Theme) Option {
+	return func(c *Converter) {
+		if t.valid() {
+			c.theme = t
+		}
+	}
+}
+
+// WithResponsive makes the art scale to the width of its container while
+// keeping its aspect ratio, for both still images and animations. Cell sizes
+// are emitted in em units of a font size derived from the container width
+// with CSS container query units, so edges stay crisp at any size. minWidth
+// and maxWidth bound the rendered width in CSS pixels; 0 leaves that side
+// unbounded, and negative values are treated as 0. A minWidth above a
+// positive maxWidth is lowered to it. Browsers without container query
+// support render one CSS pixel per output pixel. Disabled by default.
+func WithResponsive(enabled bool, minWidth, maxWidth int) Option {
+	return func(c *Converter) {
+		c.responsive = enabled
+		c.minWidth, c.maxWidth = max(minWidth, 0), max(maxWidth, 0)
+		if c.maxWidth > 0 && c.minWidth > c.maxWidth {
+			c.minWidth = c.maxWidth
+		}
+	}
+}
+
+// WithAltText describes the art for assistive technology. The art is marked
+// as a single image (role="img") named by alt, and its tables are marked as
+// presentational so screen readers do not announce rows of empty cells. A
+// non-empty description adds a visible <figcaption> that is also announced
+// as the image's description. Both are HTML-escaped. An empty alt disables
+// the metadata, which is the default.
+func WithAltText(alt, description string) Option {
+	return func(c *Converter) {
+		c.altText = alt
+		c.description = description
+		if alt == "" {
+			c.description = ""
+		}
+	}
+}
+
+// WithMinify shrinks the generated HTML: whitespace between tags and
+// indentation are removed, attribute values that need no quotes lose them,
+// optional end tags such as </td> and </tr> are omitted and the embedded CSS
+// is compacted. The output parses to the same document as the readable
+// default. Output of custom templates (see [WithTemplate]) is minified too.
+// Disabled by default.
+func WithMinify(enabled bool) Option {
+	return func(c *Converter) {
+		c.minify = enabled
+	}
+}
+
+// WithMaxBytes sets a budget for the size of the output, e.g. 100 << 10 for
+// 100 KB email. While the rendered HTML is larger, the conversion renders
+// again with a smaller target width and height and, for animations, a lower
+// frame limit (see [WithMaxFrames]), scaled by how far the output exceeds
+// the budget, and then with smaller cells (see [WithCellSize]). report, if
+// non-nil, receives the settings that fit. When the output still does not
+// fit at one cell, two frames and one pixel per cell, the conversion fails
+// with a [*MaxBytesError] reporting the smallest output. Spritesheet pages
+// are not tuned. Values of 0 or less disable the budget, which is the
+// default.
+func WithMaxBytes(n int, report func(Budget)) Option {
+	return func(c *Converter) {
+		c.maxBytes = max(n, 0)
+		c.budgetReport = report
+	}
+}
+
+// WithProfile selects the kind of HTML to emit. [ProfileEmail] produces
+// markup for HTML email, where options that rely on CSS or scripts
+// ([WithSmoothLoad], [WithObfuscation], [WithPlayer], [WithWebComponent],
+// [WithResponsive], [WithTheme] colours) and custom templates have no effect.
+// Unknown profiles are ignored. The default is [ProfileWeb].
+func WithProfile(p Profile) Option {
+	return func(c *Converter) {
+		if p == ProfileWeb || p == ProfileEmail {
+			c.profile = p
+		}
+	}
+}
+
+// WithClassPrefix sets the prefix of the CSS class and @keyframes names in the
+// output, e.g. "art" yields art-container, art-frame and art-anim-0. Use
+// distinct prefixes for outputs embedded in the same page so their rules do
+// not collide. The default is "pixcel". Prefixes that are not CSS identifiers
+// (a letter or underscore, then letters, digits, underscores or hyphens) are
+// ignored.
+func WithClassPrefix(prefix string) Option {
+	return func(c *Converter) {
+		if validPrefix(prefix) {
+			c.prefix = prefix
+		}
+	}
+}
+
+// WithUniquePrefix appends a random suffix to the class prefix (see
+// [WithClassPrefix]) on every conversion, e.g. pixcel-3fa9c1, so any number
+// of outputs can be embedded in one page without coordinating prefixes.
+// Disabled by default, which keeps the output deterministic.
+func WithUniquePrefix(enabled bool) Option {
+	return func(c *Converter) {
+		c.uniquePrefix = enabled
+	}
+}
+
+// WithTemplate replaces the built-in page layout with a custom template set.
+// The set defines a [TemplateImage] template, executed with [TemplateData]
+// for still images, and a [TemplateAnimation] template, executed with
+// [AnimationTemplateData] for animations. Parse it with [TemplateFuncs] to
+// use the helpers of the built-in templates. A set defining neither template
+// makes conversions fail with [ErrInvalidTemplate], as does converting
+// content whose template is missing. Spritesheet pages rendered by
+// [Converter.ConvertSprites] keep the built-in layout. A nil t is ignored.
+func WithTemplate(t *template.Template) Option {
+	return func(c *Converter) {
+		if t != nil {
+			c.pageTmpl = t
+			c.pageTmplErr = checkTemplate(t)
+		}
+	}
+}
+
+// WithTemplateFS is like [WithTemplate], but parses the template set from the
+// files in fsys matching patterns (default "*.tmpl"), with [TemplateFuncs]
+// available. Parse errors are reported as [ErrInvalidTemplate] when
+// converting.
+func WithTemplateFS(fsys fs.FS, patterns ...string) Option {
+	return func(c *Converter) {
+		if fsys == nil {
+			return
+		}
+		if len(patterns) == 0 {
+			patterns = []string{"*.tmpl"}
+		}
+
+		t, err := template.New("").Funcs(templateFuncs).ParseFS(fsys, patterns...)
+		if err != nil {
+			c.pageTmpl, c.pageTmplErr = nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+			return
+		}
+		c.pageTmpl, c.pageTmplErr = t, checkTemplate(t)
+	}
+}
+
+// WithFit configures how the image is mapped onto the target box when both
+// [WithTargetWidth] and [WithTargetHeight] are set. The default is [FitStretch],
+// which distorts the image to the exact box. Use [FitContain], [FitCover] or
+// [FitPad] to preserve the aspect ratio, e.g. for fixed-size 64x64 avatars.
+func WithFit(f Fit) Option {
+	return func(c *Converter) {
+		if f >= FitStretch && f <= FitPad {
+			c.fit = f
+		}
+	}
+}
+
+// WithGravity sets the anchor used by [FitCover] to choose the kept region
+// and by [FitPad] to position the image inside the padding. The default is
+// [GravityCenter].
+func WithGravity(g Gravity) Option {
+	return func(c *Converter) {
+		if g >= GravityCenter && g <= GravitySouthWest {
+			c.gravity = g
+		}
+	}
+}
+
+// WithPadColor sets the colour used to fill the padding added by [FitPad].
+// The default is fully transparent, which renders as empty cells.
+func WithPadColor(col color.Color) Option {
+	return func(c *Converter) {
+		if col != nil {
+			c.padColor = col
+		}
+	}
+}
+
+// WithCellSize sets the size, in CSS pixels, of the block each source pixel
+// renders as. The default is 1×1. Larger values enlarge the art without
+// adding cells, and non-square sizes (e.g. 2×1) emulate the wide pixels of
+// CRT-era or C64-style displays. Non-positive values are ignored.
+func WithCellSize(w, h int) Option {
+	return func(c *Converter) {
+		if w > 0 && h > 0 {
+			c.cellWidth = w
+			c.cellHeight = h
+		}
+	}
+}
+
+// WithCrop selects the region of the source image to convert, e.g. a single
+// sprite from a spritesheet. The rectangle is relative to the image's top-left
+// corner and is applied before scaling; for animated GIFs it is applied to
+// every composited frame. Parts of the region outside the image are clipped,
+// and conversion fails with [ErrInvalidCrop] if nothing remains. An empty
+// rectangle disables cropping (default).
+func WithCrop(r image.Rectangle) Option {
+	return func(c *Converter) {
+		c.crop = r.Canon()
+	}
+}
@@ -8,7 +8,9 @@ package pixcel
 import (
 	"context"
 	"image"
+	"image/color"
 	"io"
+	"text/template"
 
 	"golang.org/x/image/draw"
 )
@@ -18,12 +20,41 @@ import (
 type Converter struct {
 	targetWidth  int
 	targetHeight int
+	nativeSize   bool // ignore the target size and keep the source size
 	withHTML     bool
 	htmlTitle    string
 	smoothLoad   bool
+	webComponent bool
 	obfuscate    bool
 	scaler       draw.Scaler
 	maxFrames    int
+	sampling     FrameSampling
+	sampleFPS    float64
+	fit          Fit
+	gravity      Gravity
+	padColor     color.Color
+	cellWidth    int
+	cellHeight   int
+	crop         image.Rectangle
+	loopCount    int // -1 keeps the source's loop count
+	frameDelta   bool
+	player       bool
+	reduceMotion bool
+	posterFrame  int
+	pageTmpl     *template.Template // custom templates, nil for the built-in ones
+	pageTmplErr  error
+	theme        Theme
+	prefix       string // class and keyframe name prefix
+	uniquePrefix bool
+	responsive   bool
+	minWidth     int // responsive size limits in CSS pixels, 0 for none
+	maxWidth     int
+	altText      string
+	description  string
+	minify       bool
+	maxBytes     int // output size budget, 0 for none
+	budgetReport func(Budget)
+	profile      Profile
 }
 
 // New creates a new Converter with the provided options.
@@ -33,8 +64,15 @@ func New(opts ...Option) *Converter {
 		targetWidth: 56,
 		withHTML:    true,
 		htmlTitle:   "Go Pixel Art",
-		scaler:     draw.NearestNeighbor,
-		maxFrames:  10,
+		scaler:      draw.NearestNeighbor,
+		maxFrames:   10,
+		sampleFPS:   defaultSampleFPS,
+		prefix:      defaultPrefix,
+		padColor:    color.Transparent,
+		cellWidth:   1,
+		cellHeight:  1,
+		loopCount:   -1,
+		theme:       ThemeDark,
 	}
 
 	for _, opt := range opts {
@@ -7,16 +7,33 @@ package pixcel
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"text/template"
+	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	xdraw "golang.org/x/image/draw"
+	"golang.org/x/net/html"
 )
 
 func createTestImage() image.Image {
@@ -573,9 +590,20 @@ func TestConvertGIF_LastFrameVisibleAtEnd(t *testing.T) {
 	assert.Contains(t, output, `animation: pixcel-anim-1`)
 }
 
-func TestBuildAllKeyframes_LastFrameEndsVisible(t *testing.T) {
+// singleFrameKeyframes returns the @keyframes of each of frameCount frames
+// shown on its own layer.
+func singleFrameKeyframes(frameCount int, delays []time.Duration) [][]Keyframe {
+	windows := frameWindows(frameCount, delays)
+	kfs := make([][]Keyframe, frameCount)
+	for i := range frameCount {
+		kfs[i] = layerKeyframes(windows, []int{i})
+	}
+	return kfs
+}
+
+func TestLayerKeyframes_LastFrameEndsVisible(t *testing.T) {
 	g := createTestGIF(3, 10)
-	kfs := buildAllKeyframes(3, g)
+	kfs := singleFrameKeyframes(3, gifDelays(g))
 	require.Len(t, kfs, 3)
 
 	// First two frames should end at 100% with opacity 0.
@@ -642,11 +670,15 @@ func TestGifDelay_ZeroDelay(t *testing.T) {
 	assert.Contains(t, buf.String(), "0.200s")
 }
 
-func TestGifDelay_OutOfRange(t *testing.T) {
-	// Test gifDelay when index exceeds Delay slice length.
-	g := &gif.GIF{Delay: []int{10}}
-	d := gifDelay(g, 5) // index out of range
-	assert.InDelta(t, 0.1, d, 0.001)
+func TestFrameDuration_OutOfRange(t *testing.T) {
+	// Test frameDuration when index exceeds the delays slice length.
+	d := frameDuration([]time.Duration{250 * time.Millisecond}, 5) // index out of range
+	assert.Equal(t, 100*time.Millisecond, d)
+}
+
+func TestGifDelays(t *testing.T) {
+	g := &gif.GIF{Delay: []int{0, 7, 100}}
+	assert.Equal(t, []time.Duration{0, 70 * time.Millisecond, time.Second}, gifDelays(g))
 }
 
 func TestCompositeFrames_NoConfigDimensions(t *testing.T) {
@@ -656,7 +688,8 @@ func TestCompositeFrames_NoConfigDimensions(t *testing.T) {
 	g.Config.Height = 0
 
 	converter := New(WithTargetWidth(4))
-	composited := converter.compositeFrames(g)
+	composited, err := converter.compositeFrames(g)
+	require.NoError(t, err)
 	assert.Len(t, composited, 2)
 	assert.Equal(t, 4, composited[0].Bounds().Dx())
 }
@@ -762,10 +795,8 @@ func TestBuildRows_CancelledContext(t *testing.T) {
 	assert.ErrorIs(t, err, context.Canceled)
 }
 
-func TestBuildKeyframes_ZeroFrames(t *testing.T) {
-	g := &gif.GIF{}
-	kf := buildAllKeyframes(0, g)
-	assert.Nil(t, kf)
+func TestFrameWindows_ZeroFrames(t *testing.T) {
+	assert.Empty(t, frameWindows(0, nil))
 }
 
 func TestConvertGIF_ProportionalHeight(t *testing.T) {
@@ -1057,25 +1088,31 @@ func TestSampleFrames_PreservesFirstAndLast(t *testing.T) {
 	g := createTestGIF(10, 10)
 	c := New(WithMaxFrames(3))
 
-	composited := c.compositeFrames(g)
+	composited, err := c.compositeFrames(g)
+	require.NoError(t, err)
 	first := composited[0]
 	last := composited[len(composited)-1]
 
-	sampled, sg := c.sampleFrames(composited, g)
-	require.Len(t, sampled, 3)
-	assert.Same(t, first, sampled[0], "first frame must be preserved")
-	assert.Same(t, last, sampled[len(sampled)-1], "last frame must be preserved")
-	assert.Len(t, sg.Delay, 3)
+	a := &Animation{Frames: rgbaFrames(composited), Delays: gifDelays(g), LoopCount: 2}
+	sampled := c.sampleFrames(a)
+	require.Len(t, sampled.Frames, 3)
+	assert.Same(t, first, sampled.Frames[0], "first frame must be preserved")
+	assert.Same(t, last, sampled.Frames[len(sampled.Frames)-1], "last frame must be preserved")
+	assert.Len(t, sampled.Delays, 3)
+	assert.Equal(t, 2, sampled.LoopCount)
+	assert.Len(t, a.Frames, 10, "input animation must not be modified")
 }
 
 func TestSampleFrames_NoOp(t *testing.T) {
 	g := createTestGIF(5, 10)
 	c := New(WithMaxFrames(10))
 
-	composited := c.compositeFrames(g)
-	sampled, sg := c.sampleFrames(composited, g)
-	assert.Len(t, sampled, 5, "should not sample when under the limit")
-	assert.Same(t, g, sg, "GIF pointer should be unchanged")
+	composited, err := c.compositeFrames(g)
+	require.NoError(t, err)
+	a := &Animation{Frames: rgbaFrames(composited), Delays: gifDelays(g)}
+	sampled := c.sampleFrames(a)
+	assert.Len(t, sampled.Frames, 5, "should not sample when under the limit")
+	assert.Same(t, a, sampled, "animation pointer should be unchanged")
 }
 
 // --- Coverage: clamp255 ---
@@ -1189,3 +1226,2825 @@ func TestConvertGIF_BuildRowsError_InMultiFrameLoop(t *testing.T) {
 	err := converter.ConvertGIF(ctx, g, &buf)
 	assert.ErrorIs(t, err, context.Canceled)
 }
+
+// --- Fuzz tests ---
+
+// fuzzPalette is a small palette used by the fuzz targets so that random
+// input produces plenty of same-colour runs for the mesher to merge.
+var fuzzPalette = []color.RGBA{
+	{},
+	{R: 255, A: 255},
+	{G: 255, A: 255},
+	{B: 255, A: 255},
+	{R: 200, G: 100, B: 50, A: 128},
+}
+
+// fuzzImage builds a w×h RGBA image whose pixels are picked from fuzzPalette
+// by the bytes in data (cycling when data is shorter than the image).
+func fuzzImage(w, h int, data []byte) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			var b byte
+			if len(data) > 0 {
+				b = data[(y*w+x)%len(data)]
+			}
+			img.Set(x, y, fuzzPalette[int(b)%len(fuzzPalette)])
+		}
+	}
+	return img
+}
+
+// assertCellsTileGrid lays out rows the way a browser lays out a table with
+// colspan/rowspan and checks that every grid slot is covered exactly once by
+// a cell whose colour matches the underlying pixel.
+func assertCellsTileGrid(t *testing.T, img image.Image, rows [][]Cell, width, height int) {
+	t.Helper()
+	require.Len(t, rows, height)
+
+	covered := make([][]bool, height)
+	for i := range covered {
+		covered[i] = make([]bool, width)
+	}
+
+	for y, row := range rows {
+		x := 0
+		for _, cell := range row {
+			for x < width && covered[y][x] {
+				x++
+			}
+			require.GreaterOrEqual(t, cell.Colspan, 1)
+			require.GreaterOrEqual(t, cell.Rowspan, 1)
+			require.LessOrEqual(t, x+cell.Colspan, width, "cell overflows row %d", y)
+			require.LessOrEqual(t, y+cell.Rowspan, height, "cell overflows column %d", x)
+
+			for dy := range cell.Rowspan {
+				for dx := range cell.Colspan {
+					require.False(t, covered[y+dy][x+dx], "slot (%d,%d) covered twice", x+dx, y+dy)
+					covered[y+dy][x+dx] = true
+
+					r8, g8, b8, a8 := colorAt(img, x+dx, y+dy)
+					want := ""
+					if a8 > 0 {
+						want = formatColor(r8, g8, b8, a8, false)
+					}
+					require.Equal(t, want, cell.Color, "slot (%d,%d) colour mismatch", x+dx, y+dy)
+				}
+			}
+			x += cell.Colspan
+		}
+	}
+
+	for y := range height {
+		for x := range width {
+			require.True(t, covered[y][x], "slot (%d,%d) not covered", x, y)
+		}
+	}
+}
+
+// htmlTagPattern matches opening and closing tags, capturing the slash and name.
+var htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9-]*)[^>]*>`)
+
+// htmlVoidElements lists the elements that never have a closing tag.
+var htmlVoidElements = map[string]bool{"meta": true, "br": true, "img": true, "link": true}
+
+// assertWellFormedHTML checks that every non-void element in s is closed in
+// the right order. It is a structural check only, not a full HTML parser.
+func assertWellFormedHTML(t *testing.T, s string) {
+	t.Helper()
+	var stack []string
+	for _, m := range htmlTagPattern.FindAllStringSubmatch(s, -1) {
+		name := strings.ToLower(m[2])
+		if htmlVoidElements[name] {
+			continue
+		}
+		if m[1] == "" {
+			stack = append(stack, name)
+			continue
+		}
+		require.NotEmpty(t, stack, "unexpected </%s>", name)
+		require.Equal(t, stack[len(stack)-1], name, "mismatched </%s>", name)
+		stack = stack[:len(stack)-1]
+	}
+	require.Empty(t, stack, "unclosed elements")
+}
+
+// keyframePercentPattern matches a single keyframe selector, e.g. "12.5000% {".
+var keyframePercentPattern = regexp.MustCompile(`([0-9.]+)% \{`)
+
+// assertKeyframesMonotonic checks that the selectors inside every
+// @keyframes block in s are in non-decreasing order within [0, 100].
+func assertKeyframesMonotonic(t *testing.T, s string) {
+	t.Helper()
+	blocks := strings.Split(s, "@keyframes ")
+	for _, block := range blocks[1:] {
+		if end := strings.Index(block, "\n  }"); end >= 0 {
+			block = block[:end]
+		}
+		prev := -1.0
+		for _, m := range keyframePercentPattern.FindAllStringSubmatch(block, -1) {
+			pct, err := strconv.ParseFloat(m[1], 64)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, pct, prev, "keyframes out of order in %q", block)
+			require.LessOrEqual(t, pct, 100.0)
+			prev = pct
+		}
+	}
+}
+
+func FuzzBuildTable(f *testing.F) {
+	f.Add(uint8(4), uint8(4), []byte{1, 1, 2, 2, 1, 1, 2, 2, 3, 3, 3, 3, 0, 0, 4, 4})
+	f.Add(uint8(1), uint8(1), []byte{0})
+	f.Add(uint8(7), uint8(3), []byte{1, 2, 3})
+	f.Add(uint8(16), uint8(16), []byte{})
+
+	f.Fuzz(func(t *testing.T, w, h uint8, data []byte) {
+		width, height := int(w%32)+1, int(h%32)+1
+		img := fuzzImage(width, height, data)
+
+		rows, err := buildTable(context.Background(), img, width, height, false)
+		require.NoError(t, err)
+		assertCellsTileGrid(t, img, rows, width, height)
+	})
+}
+
+func FuzzConvert(f *testing.F) {
+	f.Add(uint8(4), uint8(4), uint8(4), uint8(0), uint8(0), uint8(0), false, []byte{1, 2, 3, 4})
+	f.Add(uint8(100), uint8(1), uint8(10), uint8(0), uint8(0), uint8(0), true, []byte{1})
+	f.Add(uint8(1), uint8(50), uint8(3), uint8(7), uint8(2), uint8(4), false, []byte{0, 1})
+	f.Add(uint8(9), uint8(3), uint8(5), uint8(5), uint8(3), uint8(8), false, []byte{2, 3})
+
+	f.Fuzz(func(t *testing.T, w, h, tw, th, fit, gravity uint8, wrap bool, data []byte) {
+		img := fuzzImage(int(w%64)+1, int(h%64)+1, data)
+		converter := New(
+			WithTargetWidth(int(tw%48)+1),
+			WithTargetHeight(int(th%48)),
+			WithFit(Fit(fit%4)),
+			WithGravity(Gravity(gravity%9)),
+			WithHTMLWrapper(wrap, "Fuzz"),
+		)
+
+		var buf bytes.Buffer
+		require.NoError(t, converter.Convert(context.Background(), img, &buf))
+		assertWellFormedHTML(t, buf.String())
+
+		scaled, err := converter.scaleImage(img)
+		require.NoError(t, err)
+		b := scaled.Bounds()
+		rows, err := buildTable(context.Background(), scaled, b.Dx(), b.Dy(), false)
+		require.NoError(t, err)
+		assertCellsTileGrid(t, scaled, rows, b.Dx(), b.Dy())
+	})
+}
+
+// fuzzGIF builds a structurally random GIF from the fuzz input. Frame
+// rectangles may fall partly or fully outside the logical screen, and the
+// Delay and Disposal slices may be shorter or longer than the frame list.
+func fuzzGIF(cfgW, cfgH, frames, delays, disposals uint8, data []byte) *gif.GIF {
+	g := &gif.GIF{}
+	g.Config.Width = int(cfgW % 12)
+	g.Config.Height = int(cfgH % 12)
+
+	next := func() int {
+		if len(data) == 0 {
+			return 0
+		}
+		b := data[0]
+		data = data[1:]
+		return int(b)
+	}
+
+	for range int(frames%6) + 1 {
+		x0, y0 := next()%16-4, next()%16-4
+		rect := image.Rect(x0, y0, x0+next()%10, y0+next()%10)
+		palette := color.Palette{fuzzPalette[1], fuzzPalette[2], fuzzPalette[3], color.Transparent}
+		frame := image.NewPaletted(rect, palette[:next()%len(palette)+1])
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				frame.SetColorIndex(x, y, uint8(next()%len(frame.Palette)))
+			}
+		}
+		g.Image = append(g.Image, frame)
+	}
+	for range int(delays % 8) {
+		g.Delay = append(g.Delay, next()%50-5)
+	}
+	for range int(disposals % 8) {
+		g.Disposal = append(g.Disposal, byte(next()%4))
+	}
+	return g
+}
+
+func FuzzConvertGIF(f *testing.F) {
+	f.Add(uint8(4), uint8(4), uint8(2), uint8(3), uint8(3), uint8(3), []byte{0, 0, 4, 4, 1, 1, 1, 1})
+	f.Add(uint8(0), uint8(0), uint8(3), uint8(0), uint8(7), uint8(1), []byte{9, 9, 2, 2, 5})
+	f.Add(uint8(8), uint8(2), uint8(5), uint8(1), uint8(0), uint8(2), []byte{3, 1, 7, 9, 2, 0, 4})
+	f.Add(uint8(3), uint8(3), uint8(1), uint8(5), uint8(5), uint8(5), []byte{})
+
+	f.Fuzz(func(t *testing.T, cfgW, cfgH, frames, delays, disposals, maxFrames uint8, data []byte) {
+		g := fuzzGIF(cfgW, cfgH, frames, delays, disposals, data)
+		converter := New(
+			WithTargetWidth(8),
+			WithHTMLWrapper(true, "Fuzz"),
+			WithMaxFrames(int(maxFrames%6)+1),
+		)
+
+		var buf bytes.Buffer
+		err := converter.ConvertGIF(context.Background(), g, &buf)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidDimensions)
+			return
+		}
+
+		output := buf.String()
+		assertWellFormedHTML(t, output)
+		assertKeyframesMonotonic(t, output)
+	})
+}
+
+func FuzzLayerKeyframes(f *testing.F) {
+	f.Add(uint8(3), []byte{10, 10, 10}, []byte{0, 1, 2})
+	f.Add(uint8(5), []byte{0, 255, 1}, []byte{0, 0, 1, 0, 1})
+	f.Add(uint8(1), []byte{}, []byte{})
+	f.Add(uint8(6), []byte{5}, []byte{0, 1, 0, 1, 1, 0})
+
+	f.Fuzz(func(t *testing.T, n uint8, delays, layers []byte) {
+		frameCount := int(n%16) + 1
+		var ds []time.Duration
+		for _, d := range delays {
+			ds = append(ds, time.Duration(int(d)-3)*10*time.Millisecond)
+		}
+
+		// Assign every frame to one of up to four layers, as identical
+		// frames share a layer.
+		byLayer := make([][]int, 4)
+		for i := range frameCount {
+			l := 0
+			if i < len(layers) {
+				l = int(layers[i] % 4)
+			}
+			byLayer[l] = append(byLayer[l], i)
+		}
+
+		windows := frameWindows(frameCount, ds)
+		percent := func(p float64) float64 {
+			v, err := strconv.ParseFloat(fmt.Sprintf("%.4f", p), 64)
+			require.NoError(t, err)
+			return v
+		}
+		for _, shown := range byLayer {
+			if len(shown) == 0 {
+				continue
+			}
+			kfs := layerKeyframes(windows, shown)
+			pcts := make([]float64, len(kfs))
+			prev := -1.0
+			for i, kf := range kfs {
+				pct, err := strconv.ParseFloat(strings.TrimSuffix(kf.Percent, "%"), 64)
+				require.NoError(t, err)
+				require.GreaterOrEqual(t, pct, prev, "layer %v keyframes out of order", shown)
+				require.LessOrEqual(t, pct, 100.0)
+				pcts[i] = pct
+				prev = pct
+			}
+			end := kfs[len(kfs)-1]
+			require.Equal(t, "100%", end.Percent)
+			require.Equal(t, shown[len(shown)-1] == frameCount-1, end.Opacity == 1)
+
+			// The layer is visible exactly while its frames are on screen.
+			for frame := range frameCount {
+				at := percent(windows[frame][0])
+				opacity := -1
+				for i, pct := range pcts[:len(pcts)-1] {
+					if pct <= at {
+						opacity = kfs[i].Opacity
+					}
+				}
+				require.Equal(t, slices.Contains(shown, frame), opacity == 1, "layer %v at frame %d", shown, frame)
+			}
+		}
+	})
+}
+
+// --- Fit tests ---
+
+// createHalvesImage returns a w×h image whose left half is red and right half is blue.
+func createHalvesImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if x < w/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestWithFit_Default(t *testing.T) {
+	c := New()
+	assert.Equal(t, FitStretch, c.fit)
+	assert.Equal(t, GravityCenter, c.gravity)
+	assert.Equal(t, color.Transparent, c.padColor)
+}
+
+func TestWithFit_InvalidIgnored(t *testing.T) {
+	c := New(WithFit(Fit(42)), WithGravity(Gravity(-1)), WithPadColor(nil))
+	assert.Equal(t, FitStretch, c.fit)
+	assert.Equal(t, GravityCenter, c.gravity)
+	assert.Equal(t, color.Transparent, c.padColor)
+}
+
+func TestScaleImage_FitContain(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	c := New(WithTargetWidth(10), WithTargetHeight(10), WithFit(FitContain))
+
+	scaled, err := c.scaleImage(img)
+	require.NoError(t, err)
+	assert.Equal(t, 10, scaled.Bounds().Dx())
+	assert.Equal(t, 5, scaled.Bounds().Dy())
+}
+
+func TestScaleImage_FitContain_Tall(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 80))
+	c := New(WithTargetWidth(16), WithTargetHeight(16), WithFit(FitContain))
+
+	scaled, err := c.scaleImage(img)
+	require.NoError(t, err)
+	assert.Equal(t, 4, scaled.Bounds().Dx())
+	assert.Equal(t, 16, scaled.Bounds().Dy())
+}
+
+func TestScaleImage_FitCover(t *testing.T) {
+	// 8x4 halves image covering a 4x4 box keeps the centre 4x4 region,
+	// i.e. two red columns followed by two blue columns.
+	c := New(WithTargetWidth(4), WithTargetHeight(4), WithFit(FitCover))
+
+	scaled, err := c.scaleImage(createHalvesImage(8, 4))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 4, 4), scaled.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, scaled.At(1, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, scaled.At(2, 0))
+}
+
+func TestScaleImage_FitCover_GravityWest(t *testing.T) {
+	c := New(WithTargetWidth(4), WithTargetHeight(4), WithFit(FitCover), WithGravity(GravityWest))
+
+	scaled, err := c.scaleImage(createHalvesImage(8, 4))
+	require.NoError(t, err)
+	for x := range 4 {
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, scaled.At(x, 3), "column %d should be red", x)
+	}
+}
+
+func TestScaleImage_FitPad(t *testing.T) {
+	img := createTestImage() // 4x4
+	pad := color.RGBA{G: 255, A: 255}
+	c := New(WithTargetWidth(8), WithTargetHeight(4), WithFit(FitPad), WithPadColor(pad))
+
+	scaled, err := c.scaleImage(img)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 8, 4), scaled.Bounds())
+	assert.Equal(t, pad, scaled.At(0, 0), "left padding")
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, scaled.At(2, 0), "image centred")
+	assert.Equal(t, pad, scaled.At(7, 3), "right padding")
+}
+
+func TestScaleImage_FitPad_GravityNorth(t *testing.T) {
+	img := createTestImage() // 4x4
+	c := New(WithTargetWidth(4), WithTargetHeight(8), WithFit(FitPad), WithGravity(GravityNorth))
+
+	scaled, err := c.scaleImage(img)
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, scaled.At(0, 0), "image anchored to top")
+	assert.Equal(t, color.RGBA{}, scaled.At(0, 7), "transparent padding below")
+}
+
+func TestScaleImage_FitIgnoredWithoutHeight(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	c := New(WithTargetWidth(10), WithFit(FitPad))
+
+	scaled, err := c.scaleImage(img)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 5), scaled.Bounds())
+}
+
+func TestGravity_Offset(t *testing.T) {
+	free := image.Pt(10, 20)
+	tests := []struct {
+		gravity Gravity
+		want    image.Point
+	}{
+		{GravityCenter, image.Pt(5, 10)},
+		{GravityNorth, image.Pt(5, 0)},
+		{GravitySouth, image.Pt(5, 20)},
+		{GravityEast, image.Pt(10, 10)},
+		{GravityWest, image.Pt(0, 10)},
+		{GravityNorthEast, image.Pt(10, 0)},
+		{GravityNorthWest, image.Pt(0, 0)},
+		{GravitySouthEast, image.Pt(10, 20)},
+		{GravitySouthWest, image.Pt(0, 20)},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.gravity.offset(free), "gravity %d", tt.gravity)
+	}
+}
+
+func TestConverter_Convert_FitPad(t *testing.T) {
+	converter := New(
+		WithTargetWidth(64),
+		WithTargetHeight(64),
+		WithFit(FitPad),
+		WithHTMLWrapper(false, ""),
+	)
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.Convert(context.Background(), createHalvesImage(100, 50), &buf))
+	assert.Contains(t, buf.String(), `<table width="64" height="64"`)
+}
+
+func TestConvertGIF_FitContain(t *testing.T) {
+	g := createTestGIF(2, 10) // 4x4
+	converter := New(
+		WithTargetWidth(8),
+		WithTargetHeight(4),
+		WithFit(FitContain),
+		WithHTMLWrapper(false, ""),
+	)
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertGIF(context.Background(), g, &buf))
+	assert.Contains(t, buf.String(), `width:4px;height:4px"`)
+	assert.Contains(t, buf.String(), `<table width="4" height="4"`)
+}
+
+func TestConvertGIF_FitPad(t *testing.T) {
+	g := createTestGIF(2, 10) // 4x4
+	converter := New(
+		WithTargetWidth(8),
+		WithTargetHeight(4),
+		WithFit(FitPad),
+		WithHTMLWrapper(false, ""),
+	)
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertGIF(context.Background(), g, &buf))
+	assert.Contains(t, buf.String(), `width:8px;height:4px"`)
+}
+
+// --- Cell size tests ---
+
+func TestWithCellSize_Default(t *testing.T) {
+	c := New()
+	assert.Equal(t, 1, c.cellWidth)
+	assert.Equal(t, 1, c.cellHeight)
+}
+
+func TestWithCellSize_Custom(t *testing.T) {
+	c := New(WithCellSize(3, 2))
+	assert.Equal(t, 3, c.cellWidth)
+	assert.Equal(t, 2, c.cellHeight)
+}
+
+func TestWithCellSize_InvalidIgnored(t *testing.T) {
+	c := New(WithCellSize(0, 4), WithCellSize(4, -1))
+	assert.Equal(t, 1, c.cellWidth)
+	assert.Equal(t, 1, c.cellHeight)
+}
+
+func TestConverter_Convert_WithCellSize(t *testing.T) {
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(false, ""), WithCellSize(3, 2))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.Convert(context.Background(), createTestImage(), &buf))
+
+	output := buf.String()
+	assert.Contains(t, output, `<table width="12" height="8"`)
+	assert.Contains(t, output, `<td colspan="4" rowspan="2" style="width:12px;height:4px;background-color:#ff0000"></td>`)
+}
+
+func TestConvertGIF_WithCellSize(t *testing.T) {
+	g := createTestGIF(2, 10)
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(true, "Cells"), WithCellSize(2, 1))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertGIF(context.Background(), g, &buf))
+
+	output := buf.String()
+	assert.Contains(t, output, "width: 8px;")
+	assert.Contains(t, output, "height: 4px;")
+	assert.Contains(t, output, `<table width="8" height="4"`)
+	assert.Contains(t, output, `style="width:8px;height:4px;`)
+}
+
+func TestConvertGIF_WithCellSize_NoHTML(t *testing.T) {
+	g := createTestGIF(2, 10)
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(false, ""), WithCellSize(5, 5))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertGIF(context.Background(), g, &buf))
+	assert.Contains(t, buf.String(), `style="position:relative;width:20px;height:20px"`)
+}
+
+// --- Crop tests ---
+
+// opaqueImage wraps an image and hides its SubImage method, forcing the
+// copying fallback in cropImage.
+type opaqueImage struct{ image.Image }
+
+func TestWithCrop_Default(t *testing.T) {
+	c := New()
+	assert.True(t, c.crop.Empty())
+}
+
+func TestWithCrop_Canonicalised(t *testing.T) {
+	c := New(WithCrop(image.Rect(4, 4, 0, 0)))
+	assert.Equal(t, image.Rect(0, 0, 4, 4), c.crop)
+}
+
+func TestScaleImage_WithCrop(t *testing.T) {
+	// Crop the blue right half of an 8x4 image; scaling 1:1 keeps it all blue.
+	c := New(WithTargetWidth(4), WithCrop(image.Rect(4, 0, 8, 4)))
+
+	scaled, err := c.scaleImage(createHalvesImage(8, 4))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 4, 4), scaled.Bounds())
+	for x := range 4 {
+		assert.Equal(t, color.RGBA{B: 255, A: 255}, scaled.At(x, 0), "column %d should be blue", x)
+	}
+}
+
+func TestScaleImage_WithCrop_ProportionalToRegion(t *testing.T) {
+	// The proportional height is derived from the cropped region, not the source.
+	c := New(WithTargetWidth(10), WithCrop(image.Rect(0, 0, 20, 10)))
+
+	scaled, err := c.scaleImage(image.NewRGBA(image.Rect(0, 0, 100, 100)))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 5), scaled.Bounds())
+}
+
+func TestScaleImage_WithCrop_ClippedToBounds(t *testing.T) {
+	c := New(WithTargetWidth(2), WithCrop(image.Rect(2, 2, 100, 100)))
+
+	img, err := c.cropImage(createTestImage())
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(2, 2, 4, 4), img.Bounds())
+}
+
+func TestScaleImage_WithCrop_RelativeToOrigin(t *testing.T) {
+	// Crop coordinates are relative to the image's top-left corner even when
+	// the image bounds do not start at (0, 0).
+	src := image.NewRGBA(image.Rect(10, 10, 14, 14))
+	c := New(WithCrop(image.Rect(1, 1, 3, 3)))
+
+	img, err := c.cropImage(src)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(11, 11, 13, 13), img.Bounds())
+}
+
+func TestCropImage_WithoutSubImage(t *testing.T) {
+	c := New(WithCrop(image.Rect(0, 2, 4, 4)))
+
+	img, err := c.cropImage(opaqueImage{createTestImage()})
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 4, 2), img.Bounds())
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, img.At(0, 0))
+}
+
+func TestConverter_Convert_CropOutsideImage(t *testing.T) {
+	converter := New(WithCrop(image.Rect(10, 10, 20, 20)))
+	var buf bytes.Buffer
+
+	err := converter.Convert(context.Background(), createTestImage(), &buf)
+	assert.ErrorIs(t, err, ErrInvalidCrop)
+}
+
+func TestConverter_Convert_WithCrop(t *testing.T) {
+	// Keep only the red top half of the test image.
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(false, ""), WithCrop(image.Rect(0, 0, 4, 2)))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.Convert(context.Background(), createTestImage(), &buf))
+
+	output := buf.String()
+	assert.Contains(t, output, `<table width="4" height="2"`)
+	assert.Contains(t, output, `background-color:#ff0000`)
+	assert.NotContains(t, output, `background-color:#0000ff`)
+}
+
+func TestCompositeFrames_WithCrop(t *testing.T) {
+	g := createTestGIF(3, 10)
+	c := New(WithCrop(image.Rect(1, 1, 3, 4)))
+
+	composited, err := c.compositeFrames(g)
+	require.NoError(t, err)
+	require.Len(t, composited, 3)
+	for i, frame := range composited {
+		assert.Equal(t, image.Rect(0, 0, 2, 3), frame.Bounds(), "frame %d", i)
+	}
+	assert.Equal(t, g.Image[2].At(1, 1), composited[2].At(0, 0))
+}
+
+func TestConvertGIF_CropOutsideImage(t *testing.T) {
+	g := createTestGIF(2, 10)
+	converter := New(WithCrop(image.Rect(8, 8, 10, 10)))
+	var buf bytes.Buffer
+
+	err := converter.ConvertGIF(context.Background(), g, &buf)
+	assert.ErrorIs(t, err, ErrInvalidCrop)
+}
+
+func TestConvertGIF_WithCrop(t *testing.T) {
+	g := createTestGIF(2, 10)
+	converter := New(WithTargetWidth(2), WithHTMLWrapper(false, ""), WithCrop(image.Rect(0, 0, 2, 1)))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertGIF(context.Background(), g, &buf))
+	assert.Contains(t, buf.String(), `<table width="2" height="1"`)
+}
+
+func TestConvertGIF_SingleFrame_WithCrop(t *testing.T) {
+	// A single-frame GIF falls back to the static path; the crop region must
+	// only be applied once.
+	g := createTestGIF(1, 10)
+	converter := New(WithTargetWidth(1), WithHTMLWrapper(false, ""), WithCrop(image.Rect(3, 3, 4, 4)))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertGIF(context.Background(), g, &buf))
+	assert.Contains(t, buf.String(), `<table width="1" height="1"`)
+}
+
+// --- Sprite tests ---
+
+// createSpriteSheet returns a 3x2 grid of 4x4 tiles separated by 1px
+// transparent gutters. Tile (row 1, col 2) is left empty.
+func createSpriteSheet() image.Image {
+	sheet := image.NewRGBA(image.Rect(0, 0, 14, 9))
+	for row := range 2 {
+		for col := range 3 {
+			if row == 1 && col == 2 {
+				continue
+			}
+			c := color.RGBA{R: uint8(60 * col), G: uint8(100 * row), B: 200, A: 255}
+			for y := range 4 {
+				for x := range 4 {
+					sheet.Set(col*5+x, row*5+y, c)
+				}
+			}
+		}
+	}
+	return sheet
+}
+
+func TestSliceGrid(t *testing.T) {
+	sprites, err := SliceGrid(createSpriteSheet(), 5, 5)
+	require.NoError(t, err)
+
+	// The 14x9 sheet holds 2x1 full 5x5 tiles; the partial row and column are dropped.
+	require.Len(t, sprites, 2)
+	assert.Equal(t, "sprite-0-0", sprites[0].Name)
+	assert.Equal(t, "sprite-0-1", sprites[1].Name)
+	assert.Equal(t, image.Rect(5, 0, 10, 5), sprites[1].Image.Bounds())
+}
+
+func TestSliceGrid_SkipsTransparentTiles(t *testing.T) {
+	sheet := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	sheet.Set(5, 1, color.RGBA{R: 255, A: 255})
+
+	sprites, err := SliceGrid(sheet, 4, 4)
+	require.NoError(t, err)
+	require.Len(t, sprites, 1)
+	assert.Equal(t, "sprite-0-1", sprites[0].Name)
+}
+
+func TestSliceGrid_Errors(t *testing.T) {
+	_, err := SliceGrid(nil, 4, 4)
+	assert.ErrorIs(t, err, ErrNilImage)
+
+	_, err = SliceGrid(createSpriteSheet(), 0, 4)
+	assert.ErrorIs(t, err, ErrInvalidTileSize)
+
+	_, err = SliceGrid(image.NewRGBA(image.Rect(0, 0, 8, 8)), 4, 4)
+	assert.ErrorIs(t, err, ErrNoSprites)
+}
+
+func TestSliceAuto(t *testing.T) {
+	sprites, err := SliceAuto(createSpriteSheet())
+	require.NoError(t, err)
+
+	require.Len(t, sprites, 5)
+	names := make([]string, len(sprites))
+	for i, s := range sprites {
+		names[i] = s.Name
+		assert.Equal(t, 4, s.Image.Bounds().Dx(), "%s width", s.Name)
+		assert.Equal(t, 4, s.Image.Bounds().Dy(), "%s height", s.Name)
+	}
+	assert.Equal(t, []string{"sprite-0-0", "sprite-0-1", "sprite-0-2", "sprite-1-0", "sprite-1-1"}, names)
+	assert.Equal(t, image.Rect(10, 0, 14, 4), sprites[2].Image.Bounds())
+}
+
+func TestSliceAuto_TrimsToVisibleRows(t *testing.T) {
+	// A 4x4 sprite beside an 8x8 one shares its 8-row band.
+	sheet := image.NewRGBA(image.Rect(0, 0, 14, 8))
+	for y := range 8 {
+		for x := range 8 {
+			sheet.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	for y := 2; y < 6; y++ {
+		for x := 10; x < 14; x++ {
+			sheet.Set(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+
+	sprites, err := SliceAuto(sheet)
+	require.NoError(t, err)
+	require.Len(t, sprites, 2)
+	assert.Equal(t, image.Rect(0, 0, 8, 8), sprites[0].Image.Bounds())
+	assert.Equal(t, image.Rect(10, 2, 14, 6), sprites[1].Image.Bounds())
+}
+
+func TestWithNativeSize(t *testing.T) {
+	sprites := []Sprite{
+		{Name: "big", Image: solidFrame(image.Point{}, 8, 8, color.RGBA{R: 255, A: 255})},
+		{Name: "small", Image: solidFrame(image.Point{}, 4, 2, color.RGBA{B: 255, A: 255})},
+	}
+	c := New(WithTargetWidth(20), WithTargetHeight(3), WithNativeSize(true))
+	var buf bytes.Buffer
+	require.NoError(t, c.ConvertSprites(context.Background(), sprites, &buf))
+	assert.Contains(t, buf.String(), `<table width="8" height="8"`)
+	assert.Contains(t, buf.String(), `<table width="4" height="2"`)
+
+	buf.Reset()
+	c = New(WithNativeSize(true), WithCrop(image.Rect(1, 1, 4, 3)))
+	require.NoError(t, c.Convert(context.Background(), sprites[0].Image, &buf))
+	assert.Contains(t, buf.String(), `<table width="3" height="2"`)
+}
+
+func TestSliceAuto_Errors(t *testing.T) {
+	_, err := SliceAuto(nil)
+	assert.ErrorIs(t, err, ErrNilImage)
+
+	_, err = SliceAuto(image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	assert.ErrorIs(t, err, ErrNoSprites)
+}
+
+func TestVisibleRuns(t *testing.T) {
+	visible := []bool{false, true, true, false, true}
+	runs := visibleRuns(len(visible), func(i int) bool { return visible[i] })
+	assert.Equal(t, [][2]int{{1, 3}, {4, 5}}, runs)
+}
+
+func TestConvertSprites(t *testing.T) {
+	sprites, err := SliceAuto(createSpriteSheet())
+	require.NoError(t, err)
+
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(true, "Sheet"))
+	var buf bytes.Buffer
+	require.NoError(t, converter.ConvertSprites(context.Background(), sprites, &buf))
+
+	output := buf.String()
+	assert.Contains(t, output, "<title>Sheet</title>")
+	assert.Equal(t, 5, strings.Count(output, `class="pixcel-sprite"`))
+	assert.Contains(t, output, `<section id="sprite-1-1" class="pixcel-sprite">`)
+	assert.Contains(t, output, `<a href="#sprite-0-2">sprite-0-2</a>`)
+	assert.Equal(t, 5, strings.Count(output, `<table width="4" height="4"`))
+	assertWellFormedHTML(t, output)
+}
+
+func TestConvertSprites_NoHTML(t *testing.T) {
+	sprites := []Sprite{{Name: `a"b`, Image: createTestImage()}}
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(false, ""))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertSprites(context.Background(), sprites, &buf))
+
+	output := buf.String()
+	assert.NotContains(t, output, "<!DOCTYPE html>")
+	assert.Contains(t, output, `<section id="a&#34;b"`, "sprite names are escaped")
+	assert.Contains(t, output, `style="border-collapse:collapse;font-size:0;line-height:0"`)
+}
+
+func TestConvertSprites_Errors(t *testing.T) {
+	c := New()
+	var buf bytes.Buffer
+
+	assert.ErrorIs(t, c.ConvertSprites(context.Background(), nil, &buf), ErrNoSprites)
+	assert.ErrorIs(t, c.ConvertSprites(context.Background(), []Sprite{{Image: createTestImage()}}, nil), ErrNilWriter)
+	assert.ErrorIs(t, c.ConvertSprites(context.Background(), []Sprite{{Name: "x"}}, &buf), ErrNilImage)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, c.ConvertSprites(ctx, []Sprite{{Image: createTestImage()}}, &buf), context.Canceled)
+
+	zero := []Sprite{{Image: image.NewRGBA(image.Rect(0, 0, 0, 0))}}
+	assert.ErrorIs(t, c.ConvertSprites(context.Background(), zero, &buf), ErrInvalidDimensions)
+}
+
+func TestConvertSprites_CancelledInBuildRows(t *testing.T) {
+	ctx := &mockContext{Context: context.Background(), cancelAt: 1}
+	sprites := []Sprite{{Image: createTestImage()}}
+
+	err := New(WithTargetWidth(4)).ConvertSprites(ctx, sprites, &bytes.Buffer{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConvertSpriteAnimation(t *testing.T) {
+	sprites, err := SliceGrid(createSpriteSheet(), 5, 5)
+	require.NoError(t, err)
+
+	converter := New(WithTargetWidth(5), WithHTMLWrapper(true, "Walk"))
+	var buf bytes.Buffer
+	require.NoError(t, converter.ConvertSpriteAnimation(context.Background(), sprites, 250*time.Millisecond, &buf))
+
+	output := buf.String()
+	assert.Equal(t, 2, strings.Count(output, `class="pixcel-frame"`))
+	assert.Contains(t, output, `animation: pixcel-anim-0 0.500s`)
+	assert.Contains(t, output, `50.0000% { opacity: 1; }`)
+}
+
+func TestConvertSpriteAnimation_SingleSprite(t *testing.T) {
+	sprites := []Sprite{{Name: "only", Image: createTestImage()}}
+	converter := New(WithTargetWidth(4), WithHTMLWrapper(false, ""))
+	var buf bytes.Buffer
+
+	require.NoError(t, converter.ConvertSpriteAnimation(context.Background(), sprites, time.Second, &buf))
+	assert.NotContains(t, buf.String(), "pixcel-frame")
+	assert.Contains(t, buf.String(), `<table width="4" height="4"`)
+}
+
+func TestConvertSpriteAnimation_Errors(t *testing.T) {
+	c := New()
+	var buf bytes.Buffer
+
+	assert.ErrorIs(t, c.ConvertSpriteAnimation(context.Background(), nil, time.Second, &buf), ErrNoSprites)
+	assert.ErrorIs(t, c.ConvertSpriteAnimation(context.Background(), []Sprite{{Image: createTestImage()}}, time.Second, nil), ErrNilWriter)
+	assert.ErrorIs(t, c.ConvertSpriteAnimation(context.Background(), []Sprite{{}}, time.Second, &buf), ErrNilImage)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, c.ConvertSpriteAnimation(ctx, []Sprite{{Image: createTestImage()}}, time.Second, &buf), context.Canceled)
+
+	cropped := New(WithCrop(image.Rect(10, 10, 12, 12)))
+	assert.ErrorIs(t, cropped.ConvertSpriteAnimation(context.Background(), []Sprite{{Image: createTestImage()}}, time.Second, &buf), ErrInvalidCrop)
+}
+
+// --- WebP tests ---
+
+// vp8lBits is a little-endian bit writer for hand-built VP8L bitstreams.
+type vp8lBits struct {
+	buf   []byte
+	nbits uint
+}
+
+func (b *vp8lBits) write(v uint32, n uint) {
+	for i := range n {
+		if b.nbits%8 == 0 {
+			b.buf = append(b.buf, 0)
+		}
+		b.buf[len(b.buf)-1] |= byte((v>>i)&1) << (b.nbits % 8)
+		b.nbits++
+	}
+}
+
+// solidVP8L returns a VP8L bitstream for a w×h image filled with c. Every
+// prefix code is a simple single-symbol code, so pixels take zero bits.
+func solidVP8L(w, h int, c color.NRGBA) []byte {
+	var b vp8lBits
+	b.write(0x2f, 8)
+	b.write(uint32(w-1), 14)
+	b.write(uint32(h-1), 14)
+	b.write(1, 1) // alpha is used
+	b.write(0, 3) // version
+	b.write(0, 1) // no transform
+	b.write(0, 1) // no color cache
+	b.write(0, 1) // no meta prefix codes
+	for _, sym := range []uint8{c.G, c.R, c.B, c.A, 0} {
+		b.write(1, 1) // simple code
+		b.write(0, 1) // one symbol
+		b.write(1, 1) // 8-bit symbol
+		b.write(uint32(sym), 8)
+	}
+	return b.buf
+}
+
+// riffChunk encodes a RIFF chunk, padding the body to an even length.
+func riffChunk(id string, body []byte) []byte {
+	out := append([]byte(id), byte(len(body)), byte(len(body)>>8), byte(len(body)>>16), byte(len(body)>>24))
+	out = append(out, body...)
+	if len(body)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+// webpFile wraps chunks in a RIFF WEBP container.
+func webpFile(chunks ...[]byte) []byte {
+	var body []byte
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	return riffChunk("RIFF", append([]byte("WEBP"), body...))
+}
+
+// vp8xChunk builds a VP8X chunk with the given flags and canvas size.
+func vp8xChunk(flags byte, w, h int) []byte {
+	body := make([]byte, 10)
+	body[0] = flags
+	putUint24(body[4:], uint32(w-1))
+	putUint24(body[7:], uint32(h-1))
+	return riffChunk("VP8X", body)
+}
+
+// anmfChunk builds an ANMF chunk holding a solid-colour VP8L frame.
+func anmfChunk(r image.Rectangle, durationMS int, flags byte, c color.NRGBA) []byte {
+	body := make([]byte, 16)
+	putUint24(body[0:], uint32(r.Min.X/2))
+	putUint24(body[3:], uint32(r.Min.Y/2))
+	putUint24(body[6:], uint32(r.Dx()-1))
+	putUint24(body[9:], uint32(r.Dy()-1))
+	putUint24(body[12:], uint32(durationMS))
+	body[15] = flags
+	body = append(body, riffChunk("VP8L", solidVP8L(r.Dx(), r.Dy(), c))...)
+	return riffChunk("ANMF", body)
+}
+
+// animatedWebP builds an animated WebP with the given canvas size and frames.
+func animatedWebP(w, h int, frames ...[]byte) []byte {
+	chunks := [][]byte{
+		vp8xChunk(webpAnimationFlag|webpAlphaFlag, w, h),
+		riffChunk("ANIM", make([]byte, 6)),
+	}
+	return webpFile(append(chunks, frames...)...)
+}
+
+var (
+	webpRed  = color.NRGBA{R: 255, A: 255}
+	webpBlue = color.NRGBA{B: 255, A: 255}
+)
+
+func TestConvertWebP_Still(t *testing.T) {
+	data := webpFile(riffChunk("VP8L", solidVP8L(4, 4, webpRed)))
+
+	var buf bytes.Buffer
+	err := New(WithTargetWidth(4)).ConvertWebP(context.Background(), bytes.NewReader(data), &buf)
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `<table width="4" height="4"`)
+	assert.Contains(t, out, "#ff0000")
+	assert.NotContains(t, out, "pixcel-frame")
+}
+
+func TestConvertWebP_Animated(t *testing.T) {
+	full := image.Rect(0, 0, 4, 4)
+	data := animatedWebP(4, 4,
+		anmfChunk(full, 100, 0, webpRed),
+		anmfChunk(full, 200, 0, webpBlue),
+	)
+
+	var buf bytes.Buffer
+	err := New(WithTargetWidth(4)).ConvertWebP(context.Background(), bytes.NewReader(data), &buf)
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, `class="pixcel-frame"`))
+	assert.Contains(t, out, "0.300s")
+	assert.Contains(t, out, "#ff0000")
+	assert.Contains(t, out, "#0000ff")
+}
+
+func TestConvertWebP_SingleFrameAnimation(t *testing.T) {
+	data := animatedWebP(4, 4, anmfChunk(image.Rect(0, 0, 4, 4), 100, 0, webpBlue))
+
+	var buf bytes.Buffer
+	err := New(WithTargetWidth(4)).ConvertWebP(context.Background(), bytes.NewReader(data), &buf)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "pixcel-frame")
+	assert.Contains(t, buf.String(), "#0000ff")
+}
+
+func TestCompositeWebPFrames_BlendAndDispose(t *testing.T) {
+	transparent := color.NRGBA{}
+	tests := []struct {
+		name       string
+		firstFlags byte
+		second     color.NRGBA
+		secondFlag byte
+		corner     color.RGBA // pixel (0,0) in the second frame
+		inner      color.RGBA // pixel (2,2) in the second frame
+	}{
+		{"blend keeps previous", 0, transparent, 0, color.RGBA{R: 255, A: 255}, color.RGBA{R: 255, A: 255}},
+		{"no-blend overwrites", 0, transparent, 0x02, color.RGBA{R: 255, A: 255}, color.RGBA{}},
+		{"dispose clears previous", 0x01, webpBlue, 0, color.RGBA{}, color.RGBA{B: 255, A: 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := animatedWebP(4, 4,
+				anmfChunk(image.Rect(0, 0, 4, 4), 100, tt.firstFlags, webpRed),
+				anmfChunk(image.Rect(2, 2, 4, 4), 100, tt.secondFlag, tt.second),
+			)
+			anim, err := decodeWebPAnimation(data)
+			require.NoError(t, err)
+			require.Len(t, anim.Frames, 2)
+
+			frames, err := New().compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+			require.NoError(t, err)
+			assert.Equal(t, color.RGBA{R: 255, A: 255}, frames[0].RGBAAt(0, 0))
+			assert.Equal(t, tt.corner, frames[1].RGBAAt(0, 0))
+			assert.Equal(t, tt.inner, frames[1].RGBAAt(2, 2))
+		})
+	}
+}
+
+func TestConvertWebP_WithCrop(t *testing.T) {
+	data := animatedWebP(8, 8,
+		anmfChunk(image.Rect(0, 0, 8, 8), 100, 0, webpRed),
+		anmfChunk(image.Rect(4, 4, 8, 8), 100, 0, webpBlue),
+	)
+
+	anim, err := decodeWebPAnimation(data)
+	require.NoError(t, err)
+	frames, err := New(WithCrop(image.Rect(4, 4, 8, 8))).compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	assert.Equal(t, image.Rect(0, 0, 4, 4), frames[1].Bounds())
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, frames[1].RGBAAt(0, 0))
+
+	_, err = New(WithCrop(image.Rect(20, 20, 30, 30))).compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+	assert.ErrorIs(t, err, ErrInvalidCrop)
+}
+
+func TestConvertWebP_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := New()
+	valid := webpFile(riffChunk("VP8L", solidVP8L(2, 2, webpRed)))
+
+	assert.ErrorIs(t, c.ConvertWebP(ctx, nil, &bytes.Buffer{}), ErrNilReader)
+	assert.ErrorIs(t, c.ConvertWebP(ctx, bytes.NewReader(valid), nil), ErrNilWriter)
+
+	invalid := map[string][]byte{
+		"not riff":       []byte("not a webp file"),
+		"wrong form":     riffChunk("RIFF", []byte("WAVEjunk")),
+		"anmf first":     webpFile(anmfChunk(image.Rect(0, 0, 2, 2), 100, 0, webpRed)),
+		"short vp8x":     webpFile(riffChunk("VP8X", make([]byte, 4))),
+		"bad still":      webpFile(riffChunk("VP8L", []byte{0x2f, 0, 0})),
+		"bad frame":      animatedWebP(2, 2, riffChunk("ANMF", make([]byte, 20))),
+		"short frame":    animatedWebP(2, 2, riffChunk("ANMF", make([]byte, 4))),
+		"no bitstream":   webpFile(riffChunk("ICCP", make([]byte, 4))),
+		"frame overflow": animatedWebP(2, 2, anmfChunk(image.Rect(2, 0, 4, 2), 100, 0, webpRed)),
+		"truncated body": valid[:len(valid)-4],
+		"huge canvas":    animatedWebP(1<<24, 1<<24, anmfChunk(image.Rect(0, 0, 1, 1), 100, 0, webpRed)),
+		"many frames":    animatedWebP(8192, 8192, slices.Repeat([][]byte{anmfChunk(image.Rect(0, 0, 2, 2), 100, 0, webpRed)}, 8)...),
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, c.ConvertWebP(ctx, bytes.NewReader(data), &bytes.Buffer{}), ErrInvalidWebP)
+		})
+	}
+
+	empty := animatedWebP(2, 2)
+	assert.ErrorIs(t, c.ConvertWebP(ctx, bytes.NewReader(empty), &bytes.Buffer{}), ErrNoFrames)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	anim := animatedWebP(2, 2,
+		anmfChunk(image.Rect(0, 0, 2, 2), 100, 0, webpRed),
+		anmfChunk(image.Rect(0, 0, 2, 2), 100, 0, webpBlue),
+	)
+	assert.ErrorIs(t, c.ConvertWebP(cancelled, bytes.NewReader(anim), &bytes.Buffer{}), context.Canceled)
+}
+
+// --- APNG tests ---
+
+// apngTestFrame describes one frame of a generated APNG.
+type apngTestFrame struct {
+	rect     image.Rectangle
+	color    color.NRGBA
+	delayNum uint16
+	delayDen uint16
+	dispose  byte
+	blend    byte
+}
+
+// pngIDAT returns an 8-bit RGBA IHDR payload and zlib-compressed,
+// unfiltered image data for a solid-colour w×h image.
+func pngIDAT(t *testing.T, w, h int, c color.NRGBA) (ihdr, idat []byte) {
+	t.Helper()
+	ihdr = binary.BigEndian.AppendUint32(nil, uint32(w))
+	ihdr = binary.BigEndian.AppendUint32(ihdr, uint32(h))
+	ihdr = append(ihdr, 8, 6, 0, 0, 0) // 8-bit RGBA, no interlace
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	for range h {
+		row := []byte{0} // filter: none
+		for range w {
+			row = append(row, c.R, c.G, c.B, c.A)
+		}
+		_, err := zw.Write(row)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return ihdr, buf.Bytes()
+}
+
+// fcTL builds the payload of an fcTL chunk for f.
+func fcTL(seq uint32, f apngTestFrame) []byte {
+	b := make([]byte, 26)
+	binary.BigEndian.PutUint32(b[0:], seq)
+	binary.BigEndian.PutUint32(b[4:], uint32(f.rect.Dx()))
+	binary.BigEndian.PutUint32(b[8:], uint32(f.rect.Dy()))
+	binary.BigEndian.PutUint32(b[12:], uint32(f.rect.Min.X))
+	binary.BigEndian.PutUint32(b[16:], uint32(f.rect.Min.Y))
+	binary.BigEndian.PutUint16(b[20:], f.delayNum)
+	binary.BigEndian.PutUint16(b[22:], f.delayDen)
+	b[24] = f.dispose
+	b[25] = f.blend
+	return b
+}
+
+// createAPNG builds a w×h APNG. The first frame is stored as the default
+// image (IDAT) and must cover the canvas; later frames use fdAT chunks.
+func createAPNG(t *testing.T, w, h int, frames ...apngTestFrame) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+
+	ihdr, _ := pngIDAT(t, w, h, color.NRGBA{})
+	writePNGChunk(&buf, "IHDR", ihdr)
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+	writePNGChunk(&buf, "acTL", actl)
+
+	seq := uint32(0)
+	for i, f := range frames {
+		writePNGChunk(&buf, "fcTL", fcTL(seq, f))
+		seq++
+		_, idat := pngIDAT(t, f.rect.Dx(), f.rect.Dy(), f.color)
+		if i == 0 {
+			writePNGChunk(&buf, "IDAT", idat)
+			continue
+		}
+		fdat := binary.BigEndian.AppendUint32(nil, seq)
+		writePNGChunk(&buf, "fdAT", append(fdat, idat...))
+		seq++
+	}
+	writePNGChunk(&buf, "IEND", nil)
+	return buf.Bytes()
+}
+
+func TestConvertAPNG_Still(t *testing.T) {
+	var src bytes.Buffer
+	require.NoError(t, png.Encode(&src, createTestImage()))
+
+	var buf bytes.Buffer
+	err := New(WithTargetWidth(4)).ConvertAPNG(context.Background(), &src, &buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "#ff0000")
+	assert.NotContains(t, buf.String(), "pixcel-frame")
+}
+
+func TestConvertAPNG_Animated(t *testing.T) {
+	full := image.Rect(0, 0, 4, 4)
+	data := createAPNG(t, 4, 4,
+		apngTestFrame{rect: full, color: webpRed, delayNum: 1, delayDen: 10},
+		apngTestFrame{rect: full, color: webpBlue, delayNum: 20, delayDen: 0},
+	)
+
+	var buf bytes.Buffer
+	err := New(WithTargetWidth(4)).ConvertAPNG(context.Background(), bytes.NewReader(data), &buf)
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, `class="pixcel-frame"`))
+	assert.Contains(t, out, "0.300s")
+	assert.Contains(t, out, "#ff0000")
+	assert.Contains(t, out, "#0000ff")
+}
+
+func TestDecodeAPNG_DefaultImageNotAFrame(t *testing.T) {
+	data := createAPNG(t, 2, 2,
+		apngTestFrame{rect: image.Rect(0, 0, 2, 2), color: webpRed},
+		apngTestFrame{rect: image.Rect(0, 0, 2, 2), color: webpBlue},
+	)
+	// Drop the first fcTL so the IDAT default image is not part of the animation.
+	chunks, err := readPNGChunks(data)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	skipped := false
+	for _, ch := range chunks {
+		if ch.Type == "fcTL" && !skipped {
+			skipped = true
+			continue
+		}
+		writePNGChunk(&buf, ch.Type, ch.Data)
+	}
+
+	anim, err := decodeAPNGAnimation(buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, anim.Frames, 1)
+	frames, err := New().compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, frames[0].RGBAAt(0, 0))
+}
+
+func TestCompositeAPNG_DisposeAndBlend(t *testing.T) {
+	full := image.Rect(0, 0, 4, 4)
+	corner := image.Rect(2, 2, 4, 4)
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	tests := []struct {
+		name   string
+		frames []apngTestFrame
+		want   []color.RGBA // pixel (2,2) per frame
+		corner []color.RGBA // pixel (0,0) per frame
+	}{
+		{
+			name: "dispose previous restores",
+			frames: []apngTestFrame{
+				{rect: full, color: webpRed},
+				{rect: corner, color: webpBlue, dispose: apngDisposePrevious},
+				{rect: image.Rect(0, 0, 1, 1), color: webpBlue, blend: apngBlendOver},
+			},
+			want:   []color.RGBA{red, blue, red},
+			corner: []color.RGBA{red, red, blue},
+		},
+		{
+			name: "dispose background clears",
+			frames: []apngTestFrame{
+				{rect: full, color: webpRed, dispose: apngDisposeBackground},
+				{rect: corner, color: webpBlue},
+			},
+			want:   []color.RGBA{red, blue},
+			corner: []color.RGBA{red, {}},
+		},
+		{
+			name: "first frame dispose previous acts as background",
+			frames: []apngTestFrame{
+				{rect: full, color: webpRed, dispose: apngDisposePrevious},
+				{rect: corner, color: webpBlue},
+			},
+			want:   []color.RGBA{red, blue},
+			corner: []color.RGBA{red, {}},
+		},
+		{
+			name: "blend source overwrites with transparency",
+			frames: []apngTestFrame{
+				{rect: full, color: webpRed},
+				{rect: corner, color: color.NRGBA{}},
+			},
+			want:   []color.RGBA{red, {}},
+			corner: []color.RGBA{red, red},
+		},
+		{
+			name: "blend over keeps previous",
+			frames: []apngTestFrame{
+				{rect: full, color: webpRed},
+				{rect: corner, color: color.NRGBA{}, blend: apngBlendOver},
+			},
+			want:   []color.RGBA{red, red},
+			corner: []color.RGBA{red, red},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anim, err := decodeAPNGAnimation(createAPNG(t, 4, 4, tt.frames...))
+			require.NoError(t, err)
+			frames, err := New().compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+			require.NoError(t, err)
+			require.Len(t, frames, len(tt.want))
+			for i := range frames {
+				assert.Equal(t, tt.want[i], frames[i].RGBAAt(2, 2), "frame %d inner", i)
+				assert.Equal(t, tt.corner[i], frames[i].RGBAAt(0, 0), "frame %d corner", i)
+			}
+		})
+	}
+}
+
+func TestConvertAPNG_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := New()
+	full := image.Rect(0, 0, 2, 2)
+	valid := createAPNG(t, 2, 2,
+		apngTestFrame{rect: full, color: webpRed},
+		apngTestFrame{rect: full, color: webpBlue},
+	)
+
+	assert.ErrorIs(t, c.ConvertAPNG(ctx, nil, &bytes.Buffer{}), ErrNilReader)
+	assert.ErrorIs(t, c.ConvertAPNG(ctx, bytes.NewReader(valid), nil), ErrNilWriter)
+
+	// rebuild replaces the payload of the first chunk of the given type.
+	rebuild := func(typ string, data []byte) []byte {
+		chunks, err := readPNGChunks(valid)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		buf.WriteString(pngSignature)
+		done := false
+		for _, ch := range chunks {
+			if ch.Type == typ && !done {
+				done = true
+				if data == nil {
+					continue
+				}
+				ch.Data = data
+			}
+			writePNGChunk(&buf, ch.Type, ch.Data)
+		}
+		return buf.Bytes()
+	}
+
+	overflow := fcTL(0, apngTestFrame{rect: image.Rect(1, 1, 3, 3)})
+
+	// corrupt flips a byte of the second frame's image data, leaving its
+	// chunk CRC stale.
+	corrupt := bytes.Clone(valid)
+	corrupt[bytes.Index(corrupt, []byte("fdAT"))+4+4+2] ^= 0xff
+
+	// huge keeps the 2×2 frames but claims a 65536×65536 canvas.
+	chunks, err := readPNGChunks(valid)
+	require.NoError(t, err)
+	ihdr := bytes.Clone(chunks[0].Data)
+	binary.BigEndian.PutUint32(ihdr[0:4], 1<<16)
+	binary.BigEndian.PutUint32(ihdr[4:8], 1<<16)
+
+	// many fits the canvas cap, but snapshots of its 1×1 frames on an
+	// 8192×8192 canvas would not fit the animation cap.
+	var many bytes.Buffer
+	many.WriteString(pngSignature)
+	big := bytes.Clone(ihdr)
+	binary.BigEndian.PutUint32(big[0:4], 8192)
+	binary.BigEndian.PutUint32(big[4:8], 8192)
+	writePNGChunk(&many, "IHDR", big)
+	writePNGChunk(&many, "acTL", make([]byte, 8))
+	_, dot := pngIDAT(t, 1, 1, webpRed)
+	for i := range 8 {
+		writePNGChunk(&many, "fcTL", fcTL(uint32(2*i), apngTestFrame{rect: image.Rect(0, 0, 1, 1), delayDen: 100}))
+		writePNGChunk(&many, "fdAT", append(binary.BigEndian.AppendUint32(nil, uint32(2*i+1)), dot...))
+	}
+	writePNGChunk(&many, "IEND", nil)
+
+	invalid := map[string][]byte{
+		"bad crc":       corrupt,
+		"huge canvas":   rebuild("IHDR", ihdr),
+		"many frames":   many.Bytes(),
+		"not png":       []byte("definitely not a png"),
+		"truncated":     valid[:len(valid)-20],
+		"short chunk":   append([]byte(pngSignature), 0, 0),
+		"no ihdr":       rebuild("IHDR", nil),
+		"short fctl":    rebuild("fcTL", make([]byte, 10)),
+		"short actl":    rebuild("acTL", make([]byte, 4)),
+		"empty frame":   rebuild("fcTL", make([]byte, 26)),
+		"frame outside": rebuild("fcTL", overflow),
+		"short fdat":    rebuild("fdAT", []byte{0}),
+		"no frame data": rebuild("IDAT", nil),
+		"bad frame":     rebuild("fdAT", []byte{0, 0, 0, 2, 1, 2, 3}),
+		"bad still":     append([]byte(pngSignature), valid[8:33]...),
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, c.ConvertAPNG(ctx, bytes.NewReader(data), &bytes.Buffer{}), ErrInvalidAPNG)
+		})
+	}
+
+	// An acTL chunk with no fcTL chunks yields no frames.
+	var empty bytes.Buffer
+	empty.WriteString(pngSignature)
+	for _, ch := range chunks {
+		if ch.Type == "IHDR" || ch.Type == "acTL" || ch.Type == "IDAT" || ch.Type == "IEND" {
+			writePNGChunk(&empty, ch.Type, ch.Data)
+		}
+	}
+	assert.ErrorIs(t, c.ConvertAPNG(ctx, &empty, &bytes.Buffer{}), ErrNoFrames)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, c.ConvertAPNG(cancelled, bytes.NewReader(valid), &bytes.Buffer{}), context.Canceled)
+}
+
+// --- Animation tests ---
+
+// solidFrame returns a w×h frame filled with c whose bounds start at origin.
+func solidFrame(origin image.Point, w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{Min: origin, Max: origin.Add(image.Pt(w, h))})
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestConvertAnimation_Procedural(t *testing.T) {
+	a := &Animation{
+		Frames: []image.Image{
+			solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255}),
+			solidFrame(image.Pt(10, 10), 4, 4, color.RGBA{G: 255, A: 255}),
+			solidFrame(image.Point{}, 4, 4, color.RGBA{B: 255, A: 255}),
+		},
+		Delays: []time.Duration{50 * time.Millisecond, 250 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(4)).ConvertAnimation(context.Background(), a, &buf))
+
+	out := buf.String()
+	assert.Equal(t, 3, strings.Count(out, `class="pixcel-frame"`))
+	assert.Contains(t, out, "#00ff00", "frames with non-zero origins are rendered")
+	// 50ms + 250ms + 100ms default for the missing third delay.
+	assert.Contains(t, out, "0.400s")
+	assert.Contains(t, out, "step-end infinite")
+	assertKeyframesMonotonic(t, out)
+}
+
+func TestConvertAnimation_LoopCount(t *testing.T) {
+	frames := []image.Image{
+		solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255}),
+		solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255}),
+	}
+	tests := []struct {
+		loop int
+		want string
+	}{
+		{0, "step-end infinite;"},
+		{-1, "step-end infinite;"},
+		{1, "step-end 1 forwards;"},
+		{3, "step-end 3 forwards;"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		a := &Animation{Frames: frames, LoopCount: tt.loop}
+		require.NoError(t, New(WithTargetWidth(2)).ConvertAnimation(context.Background(), a, &buf))
+		assert.Equal(t, 2, strings.Count(buf.String(), tt.want), "loop %d", tt.loop)
+	}
+}
+
+func TestConvertAnimation_Crop(t *testing.T) {
+	frame := func(c color.RGBA) image.Image {
+		img := solidFrame(image.Pt(5, 5), 4, 4, color.RGBA{R: 255, A: 255})
+		img.SetRGBA(7, 7, c)
+		return img
+	}
+	a := &Animation{Frames: []image.Image{frame(color.RGBA{G: 255, A: 255}), frame(color.RGBA{B: 255, A: 255})}}
+
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(1), WithCrop(image.Rect(2, 2, 3, 3)))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "#00ff00")
+	assert.Contains(t, out, "#0000ff")
+	assert.NotContains(t, out, "#ff0000")
+}
+
+func TestConvertAnimation_SingleFrame(t *testing.T) {
+	a := &Animation{Frames: []image.Image{solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255})}}
+
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(2), WithCrop(image.Rect(0, 0, 2, 2)))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	assert.NotContains(t, buf.String(), "pixcel-frame")
+	assert.Contains(t, buf.String(), `<table width="2" height="2"`)
+}
+
+func TestConvertAnimation_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := New()
+	frame := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	valid := &Animation{Frames: []image.Image{frame, frame}}
+
+	assert.ErrorIs(t, c.ConvertAnimation(ctx, nil, &bytes.Buffer{}), ErrNilAnimation)
+	assert.ErrorIs(t, c.ConvertAnimation(ctx, valid, nil), ErrNilWriter)
+	assert.ErrorIs(t, c.ConvertAnimation(ctx, &Animation{}, &bytes.Buffer{}), ErrNoFrames)
+	assert.ErrorIs(t, c.ConvertAnimation(ctx, &Animation{Frames: []image.Image{frame, nil}}, &bytes.Buffer{}), ErrNilImage)
+
+	err := New(WithCrop(image.Rect(10, 10, 20, 20))).ConvertAnimation(ctx, valid, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidCrop)
+
+	zero := image.NewRGBA(image.Rectangle{})
+	err = c.ConvertAnimation(ctx, &Animation{Frames: []image.Image{zero, zero}}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidDimensions)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, c.ConvertAnimation(cancelled, valid, &bytes.Buffer{}), context.Canceled)
+}
+
+func TestConvertAnimation_FileLoopCounts(t *testing.T) {
+	full := image.Rect(0, 0, 2, 2)
+
+	webpData := animatedWebP(2, 2,
+		anmfChunk(full, 100, 0, webpRed),
+		anmfChunk(full, 100, 0, webpBlue),
+	)
+	// Patch the ANIM loop count (after RIFF header, VP8X chunk and ANIM header).
+	webpData[12+18+8+4] = 2
+
+	apngData := createAPNG(t, 2, 2,
+		apngTestFrame{rect: full, color: webpRed},
+		apngTestFrame{rect: full, color: webpBlue},
+	)
+	chunks, err := readPNGChunks(apngData)
+	require.NoError(t, err)
+	var patched bytes.Buffer
+	patched.WriteString(pngSignature)
+	for _, ch := range chunks {
+		if ch.Type == "acTL" {
+			ch.Data = []byte{0, 0, 0, 2, 0, 0, 0, 4}
+		}
+		writePNGChunk(&patched, ch.Type, ch.Data)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(2)).ConvertWebP(context.Background(), bytes.NewReader(webpData), &buf))
+	assert.Contains(t, buf.String(), "step-end 2 forwards")
+
+	buf.Reset()
+	require.NoError(t, New(WithTargetWidth(2)).ConvertAPNG(context.Background(), &patched, &buf))
+	assert.Contains(t, buf.String(), "step-end 4 forwards")
+
+	short := webpFile(vp8xChunk(webpAnimationFlag, 2, 2), riffChunk("ANIM", make([]byte, 2)))
+	assert.ErrorIs(t, New().ConvertWebP(context.Background(), bytes.NewReader(short), &bytes.Buffer{}), ErrInvalidWebP)
+}
+
+// --- Loop count tests ---
+
+func TestConvertGIF_LoopCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		loopCount int
+		opts      []Option
+		want      string
+	}{
+		{"forever", 0, nil, "step-end infinite;"},
+		{"once", -1, nil, "step-end 1 forwards;"},
+		{"repeat twice", 2, nil, "step-end 3 forwards;"},
+		{"override finite", 0, []Option{WithLoopCount(2)}, "step-end 2 forwards;"},
+		{"override forever", -1, []Option{WithLoopCount(0)}, "step-end infinite;"},
+		{"negative override ignored", 4, []Option{WithLoopCount(-3)}, "step-end 5 forwards;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := createTestGIF(3, 10)
+			g.LoopCount = tt.loopCount
+			opts := append([]Option{WithTargetWidth(4)}, tt.opts...)
+
+			var buf bytes.Buffer
+			require.NoError(t, New(opts...).ConvertGIF(context.Background(), g, &buf))
+			assert.Equal(t, 3, strings.Count(buf.String(), tt.want))
+		})
+	}
+}
+
+func TestGifPlays(t *testing.T) {
+	assert.Equal(t, 0, gifPlays(0))
+	assert.Equal(t, 1, gifPlays(-1))
+	assert.Equal(t, 1, gifPlays(-7))
+	assert.Equal(t, 6, gifPlays(5))
+}
+
+func TestWithLoopCount(t *testing.T) {
+	assert.Equal(t, -1, New().loopCount, "default keeps the source loop count")
+	assert.Equal(t, 0, New(WithLoopCount(0)).loopCount)
+	assert.Equal(t, 3, New(WithLoopCount(3)).loopCount)
+	assert.Equal(t, -1, New(WithLoopCount(-2)).loopCount)
+}
+
+func TestLayerKeyframes_PlayOnceHoldsLastFrame(t *testing.T) {
+	// With animation-fill-mode: forwards the 100% keyframe is held after the
+	// final play, so exactly the last frame must end visible.
+	delays := []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 100 * time.Millisecond}
+	kfs := singleFrameKeyframes(3, delays)
+	require.Len(t, kfs, 3)
+
+	for i, frame := range kfs {
+		end := frame[len(frame)-1]
+		require.Equal(t, "100%", end.Percent)
+		assert.Equal(t, i == 2, end.Opacity == 1, "frame %d end opacity", i)
+	}
+	assert.Equal(t, []Keyframe{
+		{Percent: "0%", Opacity: 0},
+		{Percent: "20.0000%", Opacity: 1},
+		{Percent: "80.0000%", Opacity: 0},
+		{Percent: "100%", Opacity: 0},
+	}, kfs[1])
+}
+
+// --- Frame delta tests ---
+
+// spriteFrames returns frames with a static blue background and a single red
+// pixel moving along the top row.
+func spriteFrames(n int) []image.Image {
+	frames := make([]image.Image, n)
+	for i := range frames {
+		img := solidFrame(image.Point{}, 8, 8, color.RGBA{B: 255, A: 255})
+		img.SetRGBA(i, 0, color.RGBA{R: 255, A: 255})
+		frames[i] = img
+	}
+	return frames
+}
+
+func TestWithFrameDelta(t *testing.T) {
+	assert.False(t, New().frameDelta)
+	assert.True(t, New(WithFrameDelta(true)).frameDelta)
+}
+
+func TestConvertAnimation_FrameDelta(t *testing.T) {
+	a := &Animation{Frames: spriteFrames(4)}
+
+	var full, delta bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(8)).ConvertAnimation(context.Background(), a, &full))
+	require.NoError(t, New(WithTargetWidth(8), WithFrameDelta(true), WithCellSize(2, 3)).ConvertAnimation(context.Background(), a, &delta))
+
+	out := delta.String()
+	assert.Equal(t, 4, strings.Count(full.String(), "<table"))
+	assert.Equal(t, 1, strings.Count(out, "<table"), "only the base frame is a table")
+	assert.Less(t, delta.Len(), full.Len())
+
+	// Each delta frame restores pixel 0 and paints its own red pixel.
+	assert.Contains(t, out, `<div style="position:absolute;left:6px;top:0px;width:2px;height:3px;background-color:#ff0000"></div>`)
+	assert.Contains(t, out, `<div style="position:absolute;left:0px;top:0px;width:2px;height:3px;background-color:#0000ff"></div>`)
+
+	// The base frame stays visible under every delta frame, to the end.
+	assert.Contains(t, out, "@keyframes pixcel-anim-0 {\n    0.0000% { opacity: 1; }\n    100% { opacity: 1; }\n  }")
+	assertKeyframesMonotonic(t, out)
+}
+
+func TestConvertAnimation_FrameDeltaTransparentFallback(t *testing.T) {
+	frames := spriteFrames(3)
+	frames[1].(*image.RGBA).SetRGBA(5, 5, color.RGBA{})
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(8), WithFrameDelta(true)).ConvertAnimation(context.Background(), &Animation{Frames: frames}, &buf))
+
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, "<table"), "the frame erasing a pixel is rendered in full")
+	// The base is hidden while the full frame 1 is shown.
+	assert.Contains(t, out, "@keyframes pixcel-anim-0 {\n    0.0000% { opacity: 1; }\n    33.3333% { opacity: 0; }\n    66.6667% { opacity: 1; }\n    100% { opacity: 1; }\n  }")
+}
+
+func TestDeltaImage(t *testing.T) {
+	base := solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})
+	frame := solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})
+	frame.SetRGBA(1, 1, color.RGBA{R: 255, A: 255})
+
+	delta, ok := deltaImage(base, frame)
+	require.True(t, ok)
+	assert.Equal(t, uint8(0), delta.RGBAAt(0, 0).A)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, delta.RGBAAt(1, 1))
+
+	patches, err := buildPatches(context.Background(), delta, false)
+	require.NoError(t, err)
+	assert.Equal(t, []Patch{{X: 1, Y: 1, W: 1, H: 1, Color: "background-color:#ff0000"}}, patches)
+
+	// A semi-transparent pixel cannot be drawn over an opaque base.
+	frame.SetRGBA(0, 0, color.RGBA{R: 64, A: 128})
+	_, ok = deltaImage(base, frame)
+	assert.False(t, ok)
+
+	// Over a transparent base it can.
+	empty := image.NewRGBA(base.Rect)
+	_, ok = deltaImage(empty, frame)
+	assert.True(t, ok)
+}
+
+func TestBuildPatches_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := buildPatches(ctx, solidFrame(image.Point{}, 2, 2, color.RGBA{A: 255}), false)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// --- Duplicate frame tests ---
+
+func TestConvertAnimation_MergesConsecutiveDuplicates(t *testing.T) {
+	red := solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255})
+	blue := solidFrame(image.Point{}, 4, 4, color.RGBA{B: 255, A: 255})
+	a := &Animation{
+		Frames: []image.Image{red, red, red, blue},
+		Delays: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(4)).ConvertAnimation(context.Background(), a, &buf))
+
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, "<table"))
+	assert.Contains(t, out, "0.400s", "total duration is unchanged")
+	assert.Contains(t, out, "@keyframes pixcel-anim-0 {\n    0.0000% { opacity: 1; }\n    75.0000% { opacity: 0; }\n    100% { opacity: 0; }\n  }")
+}
+
+func TestConvertAnimation_ReusesRepeatedFrames(t *testing.T) {
+	red := solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255})
+	blue := solidFrame(image.Point{}, 4, 4, color.RGBA{B: 255, A: 255})
+	a := &Animation{Frames: []image.Image{red, blue, red, blue}}
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(4)).ConvertAnimation(context.Background(), a, &buf))
+
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, "<table"))
+	assert.Contains(t, out, "@keyframes pixcel-anim-0 {\n    0.0000% { opacity: 1; }\n    25.0000% { opacity: 0; }\n    50.0000% { opacity: 1; }\n    75.0000% { opacity: 0; }\n    100% { opacity: 0; }\n  }")
+	assert.Contains(t, out, "@keyframes pixcel-anim-1 {\n    0% { opacity: 0; }\n    25.0000% { opacity: 1; }\n    50.0000% { opacity: 0; }\n    75.0000% { opacity: 1; }\n    100% { opacity: 1; }\n  }")
+	assertKeyframesMonotonic(t, out)
+}
+
+func TestConvertAnimation_IdenticalAfterScaling(t *testing.T) {
+	// Frames differing only in a pixel that nearest-neighbour scaling drops
+	// render as a single still image.
+	a := solidFrame(image.Point{}, 8, 8, color.RGBA{G: 255, A: 255})
+	b := solidFrame(image.Point{}, 8, 8, color.RGBA{G: 255, A: 255})
+	b.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(4)).ConvertAnimation(context.Background(), &Animation{Frames: []image.Image{a, b}}, &buf))
+	assert.NotContains(t, buf.String(), "@keyframes")
+	assert.Equal(t, 1, strings.Count(buf.String(), "<table"))
+}
+
+func TestConvertAnimation_DeltaWithRepeatedBase(t *testing.T) {
+	frames := spriteFrames(2)
+	frames = append(frames, frames[0])
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(8), WithFrameDelta(true)).ConvertAnimation(context.Background(), &Animation{Frames: frames}, &buf))
+
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "<table"))
+	assert.Equal(t, 2, strings.Count(out, "@keyframes"))
+	assert.Contains(t, out, "@keyframes pixcel-anim-0 {\n    0.0000% { opacity: 1; }\n    100% { opacity: 1; }\n  }")
+}
+
+func TestUniqueFrames(t *testing.T) {
+	red := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	blue := solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})
+	red2 := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+
+	layers, shown := uniqueFrames([]*image.RGBA{red, blue, red2, blue})
+	assert.Equal(t, []*image.RGBA{red, blue}, layers)
+	assert.Equal(t, [][]int{{0, 2}, {1, 3}}, shown)
+}
+
+// --- Frame sampling tests ---
+
+// sampleAnimation returns n distinct frames of 10ms each.
+func sampleAnimation(n int) *Animation {
+	a := &Animation{}
+	for i := range n {
+		a.Frames = append(a.Frames, solidFrame(image.Point{}, 2, 2, color.RGBA{R: uint8(i * 20), A: 255}))
+		a.Delays = append(a.Delays, 10*time.Millisecond)
+	}
+	return a
+}
+
+func totalDelay(delays []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, d := range delays {
+		sum += d
+	}
+	return sum
+}
+
+func TestWithFrameSampling(t *testing.T) {
+	assert.Equal(t, SampleUniform, New().sampling)
+	assert.Equal(t, SampleDistinct, New(WithFrameSampling(SampleDistinct)).sampling)
+	assert.Equal(t, SampleUniform, New(WithFrameSampling(FrameSampling(99))).sampling)
+	assert.Equal(t, float64(defaultSampleFPS), New().sampleFPS)
+	assert.Equal(t, 24.0, New(WithSampleFPS(24)).sampleFPS)
+	assert.Equal(t, float64(defaultSampleFPS), New(WithSampleFPS(-1)).sampleFPS)
+}
+
+func TestSampleFrames_Uniform(t *testing.T) {
+	a := sampleAnimation(10)
+	sampled := New(WithMaxFrames(4)).sampleFrames(a)
+	require.Len(t, sampled.Frames, 4)
+	assert.Equal(t, 40*time.Millisecond, totalDelay(sampled.Delays), "uniform sampling speeds playback up")
+}
+
+func TestSampleFrames_Duration(t *testing.T) {
+	a := sampleAnimation(10)
+	a.Delays[1] = 0 // treated as 100ms
+
+	sampled := New(WithMaxFrames(4), WithFrameSampling(SampleDuration)).sampleFrames(a)
+	require.Len(t, sampled.Frames, 4)
+	// Indices 0, 3, 6, 9.
+	assert.Same(t, a.Frames[3], sampled.Frames[1])
+	assert.Equal(t, []time.Duration{120 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond, 10 * time.Millisecond}, sampled.Delays)
+	assert.Equal(t, 190*time.Millisecond, totalDelay(sampled.Delays))
+}
+
+func TestSampleFrames_Distinct(t *testing.T) {
+	a := sampleAnimation(6)
+	// Frames 1, 2 and 4 repeat their predecessor; only 3 changes.
+	a.Frames[1], a.Frames[2] = a.Frames[0], a.Frames[0]
+	a.Frames[4] = a.Frames[3]
+
+	sampled := New(WithMaxFrames(3), WithFrameSampling(SampleDistinct)).sampleFrames(a)
+	require.Len(t, sampled.Frames, 3)
+	assert.Same(t, a.Frames[0], sampled.Frames[0])
+	assert.Same(t, a.Frames[3], sampled.Frames[1])
+	assert.Same(t, a.Frames[5], sampled.Frames[2])
+	assert.Equal(t, []time.Duration{30 * time.Millisecond, 20 * time.Millisecond, 10 * time.Millisecond}, sampled.Delays)
+}
+
+func TestSampleFrames_FPS(t *testing.T) {
+	a := sampleAnimation(20) // 200ms at 100fps
+
+	sampled := New(WithMaxFrames(10), WithFrameSampling(SampleFPS), WithSampleFPS(25)).sampleFrames(a)
+	require.Len(t, sampled.Frames, 5)
+	for i, f := range sampled.Frames {
+		assert.Same(t, a.Frames[i*4], f)
+		assert.Equal(t, 40*time.Millisecond, sampled.Delays[i])
+	}
+
+	// A rate above the budget is lowered to fit it.
+	sampled = New(WithMaxFrames(4), WithFrameSampling(SampleFPS), WithSampleFPS(1000)).sampleFrames(a)
+	require.Len(t, sampled.Frames, 4)
+	assert.Same(t, a.Frames[5], sampled.Frames[1])
+	assert.Equal(t, 200*time.Millisecond, totalDelay(sampled.Delays))
+
+	// An uneven step leaves the remainder to the last frame.
+	sampled = New(WithMaxFrames(10), WithFrameSampling(SampleFPS), WithSampleFPS(30)).sampleFrames(a)
+	require.Len(t, sampled.Frames, 6)
+	assert.Equal(t, 200*time.Millisecond, totalDelay(sampled.Delays))
+}
+
+func TestFrameDiff(t *testing.T) {
+	black := solidFrame(image.Point{}, 2, 2, color.RGBA{A: 255})
+	white := solidFrame(image.Pt(5, 5), 2, 2, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	assert.Equal(t, 0.0, frameDiff(black, black))
+	assert.Equal(t, 255.0*3/4, frameDiff(black, white))
+	assert.Equal(t, math.MaxFloat64, frameDiff(black, solidFrame(image.Point{}, 3, 2, color.RGBA{})))
+}
+
+func TestConvertAnimation_SampleDurationKeepsLength(t *testing.T) {
+	a := sampleAnimation(20)
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(2), WithMaxFrames(5), WithFrameSampling(SampleDuration)).ConvertAnimation(context.Background(), a, &buf))
+	assert.Contains(t, buf.String(), "0.200s")
+}
+
+// --- Player tests ---
+
+func TestWithPlayer(t *testing.T) {
+	assert.False(t, New().player)
+	assert.True(t, New(WithPlayer(true)).player)
+}
+
+func TestConvertAnimation_Player(t *testing.T) {
+	red := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	blue := solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})
+	a := &Animation{
+		Frames: []image.Image{red, blue, red},
+		Delays: []time.Duration{50 * time.Millisecond, 0, 250 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(2), WithPlayer(true), WithLoopCount(3)).ConvertAnimation(context.Background(), a, &buf))
+
+	out := buf.String()
+	assert.NotContains(t, out, "@keyframes", "the script replaces the CSS animation")
+	assert.Contains(t, out, `<div class="pixcel-controls">`)
+	assert.Contains(t, out, `data-pixcel="play"`)
+	assert.Contains(t, out, `<input type="range" data-pixcel="seek" min="0" max="2" value="0" aria-label="Frame">`)
+	assert.Contains(t, out, "var frames = [[0,false,50],[1,false,100],[0,false,250]];")
+	assert.Contains(t, out, "var loops = 3;")
+	assert.Contains(t, out, "prefers-reduced-motion: reduce")
+
+	// Fragments have no place for a script and stay unchanged.
+	buf.Reset()
+	require.NoError(t, New(WithTargetWidth(2), WithPlayer(true), WithHTMLWrapper(false, "")).ConvertAnimation(context.Background(), a, &buf))
+	assert.NotContains(t, buf.String(), "<script>")
+	assert.NotContains(t, buf.String(), "pixcel-controls")
+}
+
+func TestConvertAnimation_PlayerDelta(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(8), WithPlayer(true), WithFrameDelta(true)).ConvertAnimation(context.Background(), &Animation{Frames: spriteFrames(3)}, &buf))
+	assert.Contains(t, buf.String(), "var frames = [[0,false,100],[1,true,100],[2,true,100]];")
+}
+
+// --- Reduced motion tests ---
+
+func TestWithReducedMotion(t *testing.T) {
+	c := New()
+	assert.False(t, c.reduceMotion)
+	assert.Equal(t, PosterFirst, c.posterFrame)
+
+	c = New(WithReducedMotion(true, PosterLast))
+	assert.True(t, c.reduceMotion)
+	assert.Equal(t, PosterLast, c.posterFrame)
+
+	c = New(WithReducedMotion(true, 4), WithReducedMotion(true, -2))
+	assert.Equal(t, 4, c.posterFrame, "invalid poster ignored")
+
+	assert.Equal(t, 0, New().posterIndex(3))
+	assert.Equal(t, 2, New(WithReducedMotion(true, PosterLast)).posterIndex(3))
+	assert.Equal(t, 1, New(WithReducedMotion(true, 1)).posterIndex(3))
+	assert.Equal(t, 2, New(WithReducedMotion(true, 9)).posterIndex(3))
+}
+
+func TestConvertAnimation_ReducedMotion(t *testing.T) {
+	red := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	green := solidFrame(image.Point{}, 2, 2, color.RGBA{G: 255, A: 255})
+	blue := solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})
+	a := &Animation{Frames: []image.Image{red, red, green, blue, green}}
+
+	tests := []struct {
+		name   string
+		poster int
+		layer  int
+	}{
+		{"first", PosterFirst, 1},
+		{"last", PosterLast, 2},
+		{"index after merged duplicate", 3, 3},
+		{"index reusing a layer", 4, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, New(WithTargetWidth(2), WithReducedMotion(true, tt.poster)).ConvertAnimation(context.Background(), a, &buf))
+			out := buf.String()
+			assert.Contains(t, out, "@media (prefers-reduced-motion: reduce) {\n    .pixcel-frame:nth-child(n) { animation: none; opacity: 0; }\n    .pixcel-frame:nth-child("+strconv.Itoa(tt.layer)+") { opacity: 1; }\n  }")
+			assert.Less(t, strings.LastIndex(out, "@keyframes"), strings.Index(out, "@media"), "must override the animation rules")
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(2)).ConvertAnimation(context.Background(), a, &buf))
+	assert.NotContains(t, buf.String(), "prefers-reduced-motion")
+}
+
+func TestConvertAnimation_ReducedMotionDeltaPoster(t *testing.T) {
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(8), WithFrameDelta(true), WithReducedMotion(true, PosterLast))
+	require.NoError(t, c.ConvertAnimation(context.Background(), &Animation{Frames: spriteFrames(3)}, &buf))
+	assert.Contains(t, buf.String(), "    .pixcel-frame:first-child { opacity: 1; }\n    .pixcel-frame:nth-child(3) { opacity: 1; }\n  }")
+}
+
+func TestConvertAnimation_ReducedMotionPlayer(t *testing.T) {
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(8), WithPlayer(true), WithReducedMotion(true, PosterLast))
+	require.NoError(t, c.ConvertAnimation(context.Background(), &Animation{Frames: spriteFrames(3)}, &buf))
+	assert.Contains(t, buf.String(), "var poster = 2;")
+	assert.NotContains(t, buf.String(), "@media", "the script handles reduced motion")
+}
+
+// --- Custom template tests ---
+
+const testPageTemplate = `{{define "image"}}<main title="{{.Title}}">{{.Width}}x{{.Height}}:{{range .Rows}}{{range .}}[{{.Colspan}}x{{.Rowspan}} {{.Color}}]{{end}}{{end}}</main>{{end}}` +
+	`{{define "animation"}}<main>{{len .Frames}} layers, {{.TotalDurationCSS}}, last cell {{mul .Width .CellWidth}}px</main>{{end}}`
+
+func TestWithTemplate(t *testing.T) {
+	custom := template.Must(template.New("page").Funcs(TemplateFuncs()).Parse(testPageTemplate))
+	c := New(WithTargetWidth(2), WithTemplate(custom), WithHTMLWrapper(true, "<Art>"), WithCellSize(3, 3))
+
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, c.Convert(context.Background(), img, &buf))
+	assert.Equal(t, `<main title="&lt;Art&gt;">2x2:[2x2 background-color:#ff0000]</main>`, buf.String())
+
+	buf.Reset()
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	assert.Equal(t, "<main>2 layers, 0.200s, last cell 6px</main>", buf.String())
+
+	assert.Nil(t, New(WithTemplate(nil)).pageTmpl)
+}
+
+func TestWithTemplate_MissingTemplates(t *testing.T) {
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+
+	imageOnly := template.Must(template.New("page").Parse(`{{define "image"}}still{{end}}`))
+	c := New(WithTargetWidth(2), WithTemplate(imageOnly))
+	var buf bytes.Buffer
+	require.NoError(t, c.Convert(context.Background(), img, &buf))
+	err := c.ConvertAnimation(context.Background(), a, &buf)
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.ErrorContains(t, err, `missing "animation" template`)
+
+	neither := template.Must(template.New("page").Parse(`{{define "other"}}{{end}}`))
+	err = New(WithTemplate(neither)).Convert(context.Background(), img, &buf)
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+	assert.ErrorContains(t, err, "defines neither")
+}
+
+func TestWithTemplateFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.tmpl":   {Data: []byte(testPageTemplate)},
+		"broken.html": {Data: []byte(`{{define "image"}}{{.Nope`)},
+	}
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{G: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(2), WithTemplateFS(fsys)).Convert(context.Background(), img, &buf))
+	assert.Contains(t, buf.String(), "[2x2 background-color:#00ff00]")
+
+	err := New(WithTemplateFS(fsys, "*.html")).Convert(context.Background(), img, &buf)
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+
+	err = New(WithTemplateFS(fsys, "missing.tmpl")).Convert(context.Background(), img, &buf)
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+
+	assert.Nil(t, New(WithTemplateFS(nil)).pageTmpl)
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	funcs := TemplateFuncs()
+	assert.Contains(t, funcs, "inc")
+	assert.Contains(t, funcs, "dec")
+	assert.Contains(t, funcs, "mul")
+
+	delete(funcs, "mul")
+	assert.Contains(t, TemplateFuncs(), "mul", "callers get a copy")
+}
+
+func TestWithTheme(t *testing.T) {
+	assert.Equal(t, ThemeDark, New().theme)
+	assert.Equal(t, ThemeLight, New(WithTheme(ThemeLight)).theme)
+
+	bad := ThemeLight
+	bad.Padding = -1
+	assert.Equal(t, ThemeDark, New(WithTheme(bad)).theme)
+
+	bad = ThemeLight
+	bad.Background = "red}</style><script>"
+	assert.Equal(t, ThemeDark, New(WithTheme(bad)).theme)
+}
+
+func TestConvert_Theme(t *testing.T) {
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+
+	theme := ThemeLight
+	theme.Padding = 8
+	theme.Radius = 0
+	theme.Shadow = ""
+	c := New(WithTargetWidth(2), WithTheme(theme), WithHTMLWrapper(true, "Themed"), WithPlayer(true))
+
+	var still, anim bytes.Buffer
+	require.NoError(t, c.Convert(context.Background(), img, &still))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &anim))
+	for _, html := range []string{still.String(), anim.String()} {
+		assert.Contains(t, html, "background: #f5f5f7;")
+		assert.Contains(t, html, "background: #ffffff;")
+		assert.Contains(t, html, "padding: 8px;")
+		assert.Contains(t, html, "border-radius: 0px;")
+		assert.Contains(t, html, "justify-content: center;")
+		assert.Contains(t, html, "box-shadow: 0 8px 32px rgba(0,0,0,0.4)", "an empty shadow keeps the built-in one")
+		assert.NotContains(t, html, "#16213e")
+	}
+	assert.NotContains(t, still.String(), "color: #1a1a2e;", "the text colour is for labels and controls")
+	assert.Contains(t, anim.String(), "color: #1a1a2e;")
+
+	var buf bytes.Buffer
+	c = New(WithTargetWidth(2), WithTheme(ThemeTransparent))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	html := buf.String()
+	assert.Contains(t, html, "background: transparent;")
+	assert.Contains(t, html, "display: inline-block;")
+	assert.NotContains(t, html, "justify-content")
+
+	buf.Reset()
+	sprites := []Sprite{{Name: "a", Image: img}}
+	require.NoError(t, New(WithTargetWidth(2), WithTheme(ThemeLight)).ConvertSprites(context.Background(), sprites, &buf))
+	assert.Contains(t, buf.String(), "background: #f5f5f7;")
+	assert.NotContains(t, buf.String(), "#16213e")
+}
+
+// TestConvert_DefaultThemeGolden checks that pages rendered with the default
+// theme are byte for byte the pages pixcel wrote before themes existed.
+func TestConvert_DefaultThemeGolden(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for y := range 3 {
+		for x := range 4 {
+			img.Set(x, y, color.RGBA{R: uint8(60 * x), G: uint8(80 * y), B: 200, A: 255})
+		}
+	}
+	img.Set(0, 0, color.RGBA{})
+
+	pal := color.Palette{color.RGBA{}, color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}}
+	g := &gif.GIF{Config: image.Config{Width: 2, Height: 2, ColorModel: pal}}
+	for i := range 2 {
+		p := image.NewPaletted(image.Rect(0, 0, 2, 2), pal)
+		for j := range p.Pix {
+			p.Pix[j] = uint8(1 + (i+j)%2)
+		}
+		g.Image = append(g.Image, p)
+		g.Delay = append(g.Delay, 10)
+		g.Disposal = append(g.Disposal, 0)
+	}
+
+	tests := []struct {
+		golden string
+		smooth bool
+		page   bool
+		gif    bool
+	}{
+		{"baseline_still_plain.html", false, true, false},
+		{"baseline_still_smooth.html", true, true, false},
+		{"baseline_still_plain_fragment.html", false, false, false},
+		{"baseline_gif_plain.html", false, true, true},
+		{"baseline_gif_smooth.html", true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			want, err := os.ReadFile("testdata/" + tt.golden)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			if tt.gif {
+				c := New(WithTargetWidth(2), WithSmoothLoad(tt.smooth), WithHTMLWrapper(tt.page, "Golden"))
+				require.NoError(t, c.ConvertGIF(context.Background(), g, &buf))
+			} else {
+				c := New(WithTargetWidth(4), WithSmoothLoad(tt.smooth), WithHTMLWrapper(tt.page, "Golden"))
+				require.NoError(t, c.Convert(context.Background(), img, &buf))
+			}
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+func TestWithWebComponent(t *testing.T) {
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+
+	for _, opts := range [][]Option{
+		{WithWebComponent(true)},
+		{WithWebComponent(true), WithHTMLWrapper(false, "")},
+	} {
+		c := New(append([]Option{WithTargetWidth(2)}, opts...)...)
+		var still, anim bytes.Buffer
+		require.NoError(t, c.Convert(context.Background(), img, &still))
+		require.NoError(t, c.ConvertAnimation(context.Background(), a, &anim))
+
+		for _, html := range []string{still.String(), anim.String()} {
+			assert.True(t, strings.HasPrefix(html, "<pixcel-art>\n<template>\n<style>"), html)
+			assert.Contains(t, html, "</template>\n</pixcel-art>\n<script>")
+			assert.Contains(t, html, `if (!customElements.get("pixcel-art"))`)
+			assert.Contains(t, html, "attachShadow")
+			assert.Contains(t, html, ":host {")
+			assert.NotContains(t, html, "<!DOCTYPE html>")
+			assert.NotContains(t, html, "body {")
+		}
+		assert.Contains(t, anim.String(), "@keyframes pixcel-anim-1")
+		assert.Contains(t, anim.String(), `<div class="pixcel-stage">`, "stage styled by the shadow style sheet")
+		assert.Contains(t, still.String(), `<table width="2" height="2" cellpadding="0" cellspacing="0">`)
+	}
+
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(2), WithWebComponent(true), WithPlayer(true))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	html := buf.String()
+	assert.Contains(t, html, `data-pixcel="seek"`)
+	assert.Contains(t, html, `.shadowRoot.querySelector(".pixcel-container"));`)
+	assert.Less(t, strings.Index(html, "</pixcel-art>"), strings.Index(html, "(function (box)"), "player runs after the element renders")
+}
+
+func TestWithClassPrefix(t *testing.T) {
+	assert.Equal(t, "pixcel", New().prefix)
+	assert.Equal(t, "my-art_2", New(WithClassPrefix("my-art_2")).prefix)
+	for _, bad := range []string{"", "2art", "-art", "art name", "a{b", "art.x"} {
+		assert.Equal(t, "pixcel", New(WithClassPrefix(bad)).prefix, bad)
+	}
+
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(2), WithClassPrefix("art"), WithPlayer(true), WithSmoothLoad(true))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	html := buf.String()
+	assert.Contains(t, html, `<div class="art-container">`)
+	assert.Contains(t, html, `<div class="art-stage">`)
+	assert.Contains(t, html, `box.querySelectorAll(".art-frame")`)
+	assert.Contains(t, html, `document.querySelector(".art-container").classList.add("loaded")`)
+	assert.NotContains(t, html, "pixcel-")
+}
+
+func TestConvertAnimation_FragmentStyles(t *testing.T) {
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(2), WithHTMLWrapper(false, ""), WithClassPrefix("a1"), WithPlayer(true))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	html := buf.String()
+	assert.True(t, strings.HasPrefix(html, "\n<style>"), html)
+	assert.Contains(t, html, ".a1-frame:nth-child(2) { animation: a1-anim-1 0.200s")
+	assert.Contains(t, html, "@keyframes a1-anim-0 {")
+	assert.Contains(t, html, ".a1-stage table {")
+	assert.NotContains(t, html, "<script>", "the player needs a page")
+
+	// Every rule of the fragment style sheet is scoped by the prefix.
+	css := html[strings.Index(html, "<style>")+len("<style>") : strings.Index(html, "</style>")]
+	for _, line := range strings.Split(css, "\n") {
+		if line = strings.TrimSpace(line); strings.HasSuffix(line, "{") && !strings.HasPrefix(line, "@") && !strings.HasSuffix(line, "% {") {
+			assert.True(t, strings.HasPrefix(line, ".a1-"), line)
+		}
+	}
+}
+
+func TestWithUniquePrefix(t *testing.T) {
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+	c := New(WithTargetWidth(2), WithHTMLWrapper(false, ""), WithClassPrefix("art"), WithUniquePrefix(true))
+
+	re := regexp.MustCompile(`@keyframes (art-[0-9a-f]{6})-anim-0 `)
+	var first, second bytes.Buffer
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &first))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &second))
+	m1, m2 := re.FindStringSubmatch(first.String()), re.FindStringSubmatch(second.String())
+	require.Len(t, m1, 2)
+	require.Len(t, m2, 2)
+	assert.NotEqual(t, m1[1], m2[1])
+	assert.Contains(t, first.String(), `<div class="`+m1[1]+`-stage"`)
+}
+
+func TestWithResponsive(t *testing.T) {
+	c := New(WithResponsive(true, -5, 300))
+	assert.True(t, c.responsive)
+	assert.Equal(t, 0, c.minWidth)
+	assert.Equal(t, 300, c.maxWidth)
+
+	c = New(WithResponsive(true, 500, 300))
+	assert.Equal(t, 300, c.minWidth, "min lowered to max")
+
+	c = New(WithResponsive(true, 500, 0))
+	assert.Equal(t, 500, c.minWidth, "no max keeps min")
+}
+
+func TestConvert_Responsive(t *testing.T) {
+	img := solidFrame(image.Point{}, 4, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 4, 2, color.RGBA{B: 255, A: 255})}}
+
+	for _, withHTML := range []bool{true, false} {
+		c := New(WithTargetWidth(4), WithHTMLWrapper(withHTML, ""), WithCellSize(2, 3), WithResponsive(true, 64, 512))
+		var still, anim bytes.Buffer
+		require.NoError(t, c.Convert(context.Background(), img, &still))
+		require.NoError(t, c.ConvertAnimation(context.Background(), a, &anim))
+
+		for _, html := range []string{still.String(), anim.String()} {
+			assert.Contains(t, html, `<div style="container-type:inline-size;width:100%;min-width:64px;max-width:512px">`)
+			assert.Contains(t, html, "font-size:1px;font-size:calc(100cqw / 8)")
+			assert.Contains(t, html, `style="width:8em;height:6em;`, "cell sizes scale with the font size")
+			assert.NotContains(t, html, "px;background-color")
+		}
+		assert.Contains(t, still.String(), `<table width="100%" cellpadding="0" cellspacing="0"`)
+		assert.Contains(t, anim.String(), `<table width="100%" style="border-collapse:collapse;font-size:inherit;`)
+	}
+
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(4), WithResponsive(true, 0, 200), WithFrameDelta(true))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	html := buf.String()
+	assert.Contains(t, html, "width: 4em;\n    height: 2em;", "stage sized in em")
+	assert.Contains(t, html, "left:0em;top:0em;width:4em;height:2em;", "patches sized in em")
+	assert.Contains(t, html, "max-width: calc(200px + 48px);", "card grows with the art")
+	assert.NotContains(t, html, "min-width:")
+
+	buf.Reset()
+	require.NoError(t, New(WithTargetWidth(4), WithResponsive(true, 0, 0), WithWebComponent(true)).Convert(context.Background(), img, &buf))
+	assert.Contains(t, buf.String(), "display: block;")
+	assert.Contains(t, buf.String(), `<div style="container-type:inline-size;width:100%">`)
+}
+
+func TestWithAltText(t *testing.T) {
+	c := New(WithAltText("", "ignored"))
+	assert.Empty(t, c.altText)
+	assert.Empty(t, c.description, "a description needs alt text")
+
+	img := solidFrame(image.Point{}, 2, 2, color.RGBA{R: 255, A: 255})
+	a := &Animation{Frames: []image.Image{img, solidFrame(image.Point{}, 2, 2, color.RGBA{B: 255, A: 255})}}
+
+	for _, opts := range [][]Option{
+		{WithHTMLWrapper(true, "")},
+		{WithHTMLWrapper(false, "")},
+		{WithWebComponent(true)},
+		{WithResponsive(true, 0, 0), WithFrameDelta(true)},
+	} {
+		c := New(append([]Option{WithTargetWidth(2), WithAltText(`A "red" <square>`, "Drawn by hand & scaled")}, opts...)...)
+		var still, anim bytes.Buffer
+		require.NoError(t, c.Convert(context.Background(), img, &still))
+		require.NoError(t, c.ConvertAnimation(context.Background(), a, &anim))
+
+		for _, html := range []string{still.String(), anim.String()} {
+			assert.Equal(t, 1, strings.Count(html, `role="img"`))
+			assert.Contains(t, html, `<div role="img" aria-label="A &#34;red&#34; &lt;square&gt;" aria-describedby="pixcel-desc">`)
+			assert.Contains(t, html, `<figcaption id="pixcel-desc">Drawn by hand &amp; scaled</figcaption>`)
+			assert.Equal(t, strings.Count(html, "<table "), strings.Count(html, `role="presentation"`), "every table is presentational")
+			assert.Less(t, strings.Index(html, `role="img"`), strings.Index(html, "<table "), "tables are inside the image")
+			assert.Less(t, strings.LastIndex(html, "</table>"), strings.Index(html, "<figcaption"))
+		}
+	}
+
+	var buf bytes.Buffer
+	c = New(WithTargetWidth(2), WithAltText("Blink", ""), WithPlayer(true))
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	html := buf.String()
+	assert.Contains(t, html, `<div role="img" aria-label="Blink">`)
+	assert.NotContains(t, html, "figure")
+	assert.Contains(t, html, "</table>\n</div>\n</div>\n</div>\n<div class=\"pixcel-controls\">", "controls stay outside the image")
+
+	buf.Reset()
+	require.NoError(t, New(WithTargetWidth(2)).Convert(context.Background(), img, &buf))
+	assert.NotContains(t, buf.String(), "role=")
+}
+
+// domString parses markup and prints its document tree one node per line,
+// ignoring whitespace-only text, collapsing whitespace in text and comparing
+// style and script contents without whitespace.
+func domString(t *testing.T, markup string) string {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(markup))
+	require.NoError(t, err)
+
+	var b strings.Builder
+	var walk func(n *html.Node, depth int)
+	walk = func(n *html.Node, depth int) {
+		indent := strings.Repeat("  ", depth)
+		switch n.Type {
+		case html.ElementNode:
+			attrs := make([]string, 0, len(n.Attr))
+			for _, a := range n.Attr {
+				attrs = append(attrs, a.Key+"="+strconv.Quote(a.Val))
+			}
+			slices.Sort(attrs)
+			b.WriteString(indent + n.Data + " " + strings.Join(attrs, " ") + "\n")
+		case html.TextNode:
+			text := strings.Join(strings.Fields(n.Data), " ")
+			if p := n.Parent; p != nil && (p.Data == "style" || p.Data == "script") {
+				// Compare code without whitespace or the optional last
+				// semicolon of CSS blocks.
+				text = strings.ReplaceAll(strings.Join(strings.Fields(n.Data), ""), ";}", "}")
+			}
+			if text != "" {
+				b.WriteString(indent + strconv.Quote(text) + "\n")
+			}
+		case html.DoctypeNode:
+			b.WriteString(indent + "<!" + n.Data + ">\n")
+		}
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			walk(ch, depth+1)
+		}
+	}
+	walk(doc, 0)
+	return b.String()
+}
+
+func TestWithMinify(t *testing.T) {
+	img := createTestImage()
+	a := &Animation{Frames: []image.Image{
+		solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255}),
+		solidFrame(image.Point{}, 4, 4, color.RGBA{G: 255, A: 255}),
+		solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255}),
+	}}
+	sprites := []Sprite{{Name: "a", Image: img}, {Name: "b", Image: img}}
+
+	for name, opts := range map[string][]Option{
+		"page":       {WithSmoothLoad(true)},
+		"fragment":   {WithHTMLWrapper(false, "")},
+		"player":     {WithPlayer(true), WithReducedMotion(true, PosterLast)},
+		"delta":      {WithFrameDelta(true), WithLoopCount(2)},
+		"component":  {WithWebComponent(true), WithAltText("Art", "A <small> test")},
+		"responsive": {WithResponsive(true, 10, 100), WithTheme(ThemeLight)},
+		"obfuscated": {WithObfuscation(true), WithCellSize(2, 1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]Option{WithTargetWidth(4)}, opts...)
+			render := func(c *Converter) (still, anim, sheet string) {
+				var b1, b2, b3 bytes.Buffer
+				require.NoError(t, c.Convert(context.Background(), img, &b1))
+				require.NoError(t, c.ConvertAnimation(context.Background(), a, &b2))
+				require.NoError(t, c.ConvertSprites(context.Background(), sprites, &b3))
+				return b1.String(), b2.String(), b3.String()
+			}
+			pretty := New(opts...)
+			minified := New(append(opts, WithMinify(true))...)
+			if name == "obfuscated" {
+				// Obfuscated colours are random; compare the same render.
+				p1, p2, p3 := render(pretty)
+				for _, p := range []string{p1, p2, p3} {
+					m := string(minifyHTML([]byte(p)))
+					assert.Less(t, len(m), len(p))
+					assert.Equal(t, domString(t, p), domString(t, m))
+				}
+				return
+			}
+
+			p1, p2, p3 := render(pretty)
+			m1, m2, m3 := render(minified)
+			for i, pair := range [][2]string{{p1, m1}, {p2, m2}, {p3, m3}} {
+				p, m := pair[0], pair[1]
+				assert.Less(t, len(m), len(p), i)
+				assert.Equal(t, domString(t, p), domString(t, m), i)
+				assert.NotContains(t, m, "</td>")
+				assert.NotContains(t, m, "</tr>")
+				assert.NotContains(t, m, "\n  ", "no indentation")
+			}
+		})
+	}
+}
+
+func TestMinifyHTML(t *testing.T) {
+	in := `<!DOCTYPE html>
+<html lang="en">
+<head>
+<!-- comment -->
+<style>
+  /* rule */
+  .a-frame:nth-child(2) , .b   { opacity: 1; box-shadow: 0 0 0 1px rgba(0, 0, 0, 0.1); }
+  @media (prefers-reduced-motion: reduce) { .a :hover { width: calc(2px + 1em); } }
+</style>
+</head>
+<body>
+<p title="two words" data-x="" class="x">  Hello,
+   world  </p>
+<table width="4"><tbody>
+<tr><td colspan="2" style="width:2px;background-color:#fff"></td></tr>
+</tbody></table>
+<select><option value="1" selected>1&#xD7;</option></select>
+<script>
+  var a = 1
+  var b = a
+</script>
+</body>
+</html>`
+	want := `<!DOCTYPE html><html lang=en><head><style>` +
+		`.a-frame:nth-child(2),.b{opacity:1;box-shadow:0 0 0 1px rgba(0,0,0,0.1)}` +
+		`@media (prefers-reduced-motion:reduce){.a :hover{width:calc(2px + 1em)}}` +
+		`</style></head><body><p title="two words" data-x="" class=x> Hello, world </p>` +
+		`<table width=4><tbody><tr><td colspan=2 style=width:2px;background-color:#fff></table>` +
+		`<select><option value=1 selected>1&#xD7;</select>` +
+		"<script>var a = 1\nvar b = a</script></body></html>"
+	assert.Equal(t, want, string(minifyHTML([]byte(in))))
+	// The CSS comment is the only content the minifier drops.
+	assert.Equal(t, domString(t, strings.Replace(in, "/* rule */", "", 1)), domString(t, want))
+}
+
+func TestNewCompressWriter(t *testing.T) {
+	img := createTestImage()
+	var plain bytes.Buffer
+	require.NoError(t, New().Convert(context.Background(), img, &plain))
+
+	decoders := map[Encoding]func(io.Reader) (io.Reader, error){
+		EncodingGzip: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		EncodingBrotli: func(r io.Reader) (io.Reader, error) {
+			return brotli.NewReader(r), nil
+		},
+	}
+	for enc, decode := range decoders {
+		t.Run(string(enc), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewCompressWriter(&buf, enc)
+			require.NoError(t, err)
+			require.NoError(t, New().Convert(context.Background(), img, w))
+			require.NoError(t, w.Close())
+
+			r, err := decode(&buf)
+			require.NoError(t, err)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, plain.String(), string(got))
+		})
+	}
+
+	assert.Equal(t, ".gz", EncodingGzip.Ext())
+	assert.Equal(t, ".br", EncodingBrotli.Ext())
+	assert.Empty(t, Encoding("zstd").Ext())
+
+	_, err := NewCompressWriter(io.Discard, "zstd")
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+	_, err = NewCompressWriter(nil, EncodingGzip)
+	assert.ErrorIs(t, err, ErrNilWriter)
+}
+
+// noiseImage returns a w×h image whose neighbouring pixels rarely share a
+// colour, so that meshing merges few cells; seed varies the pattern.
+func noiseImage(w, h, seed int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			v := (x*31 + y*17 + seed*7) * 2654435761
+			img.Set(x, y, color.RGBA{R: uint8(v >> 8), G: uint8(v >> 16), B: uint8(v>>24) | 1, A: 255})
+		}
+	}
+	return img
+}
+
+func TestWithMaxBytes(t *testing.T) {
+	img := noiseImage(64, 64, 0)
+	var full bytes.Buffer
+	require.NoError(t, New(WithTargetWidth(64)).Convert(context.Background(), img, &full))
+
+	t.Run("still", func(t *testing.T) {
+		var b Budget
+		var buf bytes.Buffer
+		limit := full.Len() / 3
+		c := New(WithTargetWidth(64), WithMaxBytes(limit, func(got Budget) { b = got }))
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+
+		assert.LessOrEqual(t, buf.Len(), limit)
+		assert.Equal(t, buf.Len(), b.Bytes)
+		assert.Less(t, b.Width, 64)
+		assert.Greater(t, b.Attempts, 1)
+		assert.Zero(t, b.MaxFrames)
+
+		// The output is what the reported settings render.
+		var want bytes.Buffer
+		require.NoError(t, New(WithTargetWidth(b.Width)).Convert(context.Background(), img, &want))
+		assert.Equal(t, want.String(), buf.String())
+	})
+
+	t.Run("fits", func(t *testing.T) {
+		var b Budget
+		var buf bytes.Buffer
+		c := New(WithTargetWidth(64), WithMaxBytes(full.Len(), func(got Budget) { b = got }))
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+		assert.Equal(t, full.String(), buf.String())
+		assert.Equal(t, Budget{Width: 64, CellWidth: 1, CellHeight: 1, Bytes: full.Len(), Attempts: 1}, b)
+	})
+
+	t.Run("fixed height", func(t *testing.T) {
+		var b Budget
+		var buf bytes.Buffer
+		c := New(WithTargetWidth(64), WithTargetHeight(32), WithMaxBytes(full.Len()/4, func(got Budget) { b = got }))
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+		assert.LessOrEqual(t, buf.Len(), full.Len()/4)
+		assert.Less(t, b.Height, 32)
+		assert.Positive(t, b.Height)
+	})
+
+	t.Run("animation", func(t *testing.T) {
+		a := &Animation{}
+		for i := range 12 {
+			a.Frames = append(a.Frames, noiseImage(32, 32, i+1))
+		}
+		var whole bytes.Buffer
+		require.NoError(t, New(WithTargetWidth(32), WithMaxFrames(0)).ConvertAnimation(context.Background(), a, &whole))
+
+		var b Budget
+		var buf bytes.Buffer
+		limit := whole.Len() / 4
+		c := New(WithTargetWidth(32), WithMaxFrames(0), WithMaxBytes(limit, func(got Budget) { b = got }))
+		require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+		assert.LessOrEqual(t, buf.Len(), limit)
+		assert.Less(t, b.MaxFrames, 12)
+		assert.GreaterOrEqual(t, b.MaxFrames, 2)
+		assert.Contains(t, buf.String(), "@keyframes")
+	})
+
+	t.Run("height at one cell wide", func(t *testing.T) {
+		opts := []Option{WithTargetWidth(1), WithHTMLWrapper(false, "")}
+		var want bytes.Buffer
+		require.NoError(t, New(append(opts, WithTargetHeight(16))...).Convert(context.Background(), img, &want))
+
+		var b Budget
+		var buf bytes.Buffer
+		c := New(append(opts, WithTargetHeight(64), WithMaxBytes(want.Len(), func(got Budget) { b = got }))...)
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+		assert.LessOrEqual(t, buf.Len(), want.Len())
+		assert.Equal(t, 1, b.Width)
+		assert.LessOrEqual(t, b.Height, 16)
+	})
+
+	t.Run("cell size", func(t *testing.T) {
+		opts := []Option{WithTargetWidth(1), WithTargetHeight(1), WithHTMLWrapper(false, "")}
+		var want bytes.Buffer
+		require.NoError(t, New(append(opts, WithCellSize(8, 8))...).Convert(context.Background(), img, &want))
+
+		var b Budget
+		var buf bytes.Buffer
+		c := New(append(opts, WithCellSize(512, 512), WithMaxBytes(want.Len(), func(got Budget) { b = got }))...)
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+		assert.LessOrEqual(t, buf.Len(), want.Len())
+		assert.LessOrEqual(t, b.CellWidth, 8)
+		assert.Equal(t, b.CellWidth, b.CellHeight)
+		assert.Contains(t, buf.String(), fmt.Sprintf("width:%dpx", b.CellWidth))
+	})
+
+	t.Run("native size", func(t *testing.T) {
+		var b Budget
+		var buf bytes.Buffer
+		c := New(WithNativeSize(true), WithMaxBytes(full.Len()/3, func(got Budget) { b = got }))
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+		assert.LessOrEqual(t, buf.Len(), full.Len()/3)
+		assert.Less(t, b.Width, 64)
+		assert.Positive(t, b.Height)
+
+		buf.Reset()
+		c = New(WithNativeSize(true), WithMaxBytes(full.Len(), func(got Budget) { b = got }))
+		require.NoError(t, c.Convert(context.Background(), img, &buf))
+		assert.Equal(t, full.String(), buf.String(), "the native size is kept while it fits")
+		assert.Equal(t, 1, b.Attempts)
+	})
+
+	t.Run("impossible", func(t *testing.T) {
+		called := false
+		var buf bytes.Buffer
+		c := New(WithTargetWidth(64), WithCellSize(4, 4), WithMaxBytes(100, func(Budget) { called = true }))
+		err := c.Convert(context.Background(), img, &buf)
+		require.ErrorIs(t, err, ErrMaxBytes)
+		assert.False(t, called)
+		assert.Zero(t, buf.Len())
+
+		var budgetErr *MaxBytesError
+		require.True(t, errors.As(err, &budgetErr))
+		assert.Equal(t, 100, budgetErr.Limit)
+		assert.Equal(t, 1, budgetErr.Budget.Width)
+		assert.Equal(t, 1, budgetErr.Budget.CellWidth)
+		assert.Greater(t, budgetErr.Budget.Bytes, 100)
+		assert.Greater(t, budgetErr.Budget.Attempts, 1)
+		assert.Contains(t, err.Error(), fmt.Sprintf("smallest output is %d bytes, budget is 100", budgetErr.Budget.Bytes))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, New(WithTargetWidth(64), WithMaxBytes(-1, nil)).Convert(context.Background(), img, &buf))
+		assert.Equal(t, full.String(), buf.String())
+	})
+}
+
+// assertEmailSafe checks that s uses none of the constructs email clients
+// strip or reject.
+func assertEmailSafe(t *testing.T, s string) {
+	t.Helper()
+	for _, banned := range []string{"<style", "style=", "class=", "<script", "<template", "@keyframes", "background-color", "<pixcel-art", "<div"} {
+		assert.NotContains(t, s, banned)
+	}
+	assert.Contains(t, s, "bgcolor=")
+}
+
+func TestWithProfile_Email(t *testing.T) {
+	img := createTestImage()
+	every := []Option{
+		WithProfile(ProfileEmail),
+		WithSmoothLoad(true),
+		WithObfuscation(true),
+		WithPlayer(true),
+		WithWebComponent(true),
+		WithResponsive(true, 0, 0),
+		WithCellSize(2, 3),
+	}
+
+	t.Run("page", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, New(every...).Convert(context.Background(), img, &buf))
+		out := buf.String()
+		assertEmailSafe(t, out)
+		assertWellFormedHTML(t, out)
+		assert.Contains(t, out, "<title>Go Pixel Art</title>")
+		assert.Contains(t, out, `<td align="center">`)
+		assert.Regexp(t, `<td( colspan="\d+")?( rowspan="\d+")? width="\d+" height="\d+" bgcolor="#[0-9a-f]{6}">`, out)
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		var buf bytes.Buffer
+		opts := append(slices.Clone(every), WithHTMLWrapper(false, ""))
+		require.NoError(t, New(opts...).Convert(context.Background(), img, &buf))
+		out := buf.String()
+		assertEmailSafe(t, out)
+		assert.True(t, strings.HasPrefix(out, "\n<table width="), out)
+		assert.NotContains(t, out, "<html")
+	})
+
+	t.Run("cells", func(t *testing.T) {
+		// The pixel layout matches the default profile's table.
+		half := createHalvesImage(4, 4)
+		var web, email bytes.Buffer
+		require.NoError(t, New(WithTargetWidth(4)).Convert(context.Background(), half, &web))
+		require.NoError(t, New(WithTargetWidth(4), WithProfile(ProfileEmail)).Convert(context.Background(), half, &email))
+		assert.Equal(t, strings.Count(web.String(), "<td"), strings.Count(email.String(), "<td")-1) // plus the centring cell
+	})
+
+	t.Run("translucent", func(t *testing.T) {
+		px := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+		px.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+		px.SetNRGBA(1, 0, color.NRGBA{G: 255, A: 200})
+		px.SetNRGBA(2, 0, color.NRGBA{B: 255, A: 50})
+		var buf bytes.Buffer
+		require.NoError(t, New(WithTargetWidth(3), WithProfile(ProfileEmail), WithHTMLWrapper(false, "")).Convert(context.Background(), px, &buf))
+		out := buf.String()
+		assert.Contains(t, out, `bgcolor="#ff0000"`)
+		assert.Contains(t, out, `bgcolor="#00ff00"`)
+		assert.NotContains(t, out, `#0000ff`)
+		assert.Equal(t, 3, strings.Count(out, "<td"))
+	})
+
+	t.Run("animation", func(t *testing.T) {
+		a := &Animation{Frames: []image.Image{
+			solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255}),
+			solidFrame(image.Point{}, 4, 4, color.RGBA{G: 255, A: 255}),
+			solidFrame(image.Point{}, 4, 4, color.RGBA{B: 255, A: 255}),
+		}}
+		var buf bytes.Buffer
+		require.NoError(t, New(append(slices.Clone(every), WithTargetWidth(4))...).ConvertAnimation(context.Background(), a, &buf))
+		out := buf.String()
+		assertEmailSafe(t, out)
+		assert.Contains(t, out, `bgcolor="#ff0000"`)
+		assert.NotContains(t, out, `#00ff00`)
+
+		buf.Reset()
+		opts := append(slices.Clone(every), WithTargetWidth(4), WithReducedMotion(true, PosterLast))
+		require.NoError(t, New(opts...).ConvertAnimation(context.Background(), a, &buf))
+		assertEmailSafe(t, buf.String())
+		assert.Contains(t, buf.String(), `bgcolor="#0000ff"`)
+	})
+
+	t.Run("gif", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, New(every...).ConvertGIF(context.Background(), createTestGIF(3, 10), &buf))
+		assertEmailSafe(t, buf.String())
+	})
+
+	t.Run("sprites", func(t *testing.T) {
+		sprites := []Sprite{
+			{Name: "red", Image: solidFrame(image.Point{}, 4, 4, color.RGBA{R: 255, A: 255})},
+			{Name: "blue", Image: solidFrame(image.Point{}, 4, 2, color.RGBA{B: 255, A: 255})},
+		}
+		var buf bytes.Buffer
+		opts := append(slices.Clone(every), WithNativeSize(true), WithHTMLWrapper(true, "Sheet"))
+		require.NoError(t, New(opts...).ConvertSprites(context.Background(), sprites, &buf))
+		out := buf.String()
+		assertEmailSafe(t, out)
+		assertWellFormedHTML(t, out)
+		assert.Contains(t, out, "<title>Sheet</title>")
+		assert.Contains(t, out, `<p id="red">red</p>`)
+		assert.Contains(t, out, `<p id="blue">blue</p>`)
+		assert.Contains(t, out, `<table width="8" height="12"`)
+		assert.Contains(t, out, `<table width="8" height="6"`)
+		assert.Contains(t, out, `bgcolor="#ff0000"`)
+		assert.Contains(t, out, `bgcolor="#0000ff"`)
+		assert.Equal(t, 1, strings.Count(out, "<html"))
+
+		buf.Reset()
+		opts = append(slices.Clone(every), WithHTMLWrapper(false, ""))
+		require.NoError(t, New(opts...).ConvertSprites(context.Background(), sprites, &buf))
+		assertEmailSafe(t, buf.String())
+		assert.NotContains(t, buf.String(), "<html")
+		assert.Equal(t, 2, strings.Count(buf.String(), `role="presentation"`))
+
+		require.ErrorIs(t, New(every...).ConvertSprites(context.Background(), []Sprite{{Name: "nil"}}, &buf), ErrNilImage)
+	})
+
+	t.Run("alt text", func(t *testing.T) {
+		var buf bytes.Buffer
+		opts := append(slices.Clone(every), WithAltText("Logo", "A <b>bold</b> logo"))
+		require.NoError(t, New(opts...).Convert(context.Background(), img, &buf))
+		out := buf.String()
+		assertEmailSafe(t, out)
+		assert.Contains(t, out, `role="img" aria-label="Logo" aria-describedby="pixcel-desc"`)
+		assert.Contains(t, out, `<p id="pixcel-desc">A &lt;b&gt;bold&lt;/b&gt; logo</p>`)
+	})
+
+	t.Run("minify", func(t *testing.T) {
+		var buf bytes.Buffer
+		opts := append(slices.Clone(every), WithMinify(true))
+		require.NoError(t, New(opts...).Convert(context.Background(), img, &buf))
+		assertEmailSafe(t, buf.String())
+		assert.NotContains(t, buf.String(), "</td>")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		c := New(WithProfile(ProfileEmail), WithProfile(Profile(9)))
+		assert.Equal(t, ProfileEmail, c.profile)
+	})
+}
+
+func TestConvert_ResponsiveTransparentBand(t *testing.T) {
+	// The top half is fully transparent, so its row band holds no coloured
+	// cell and only the cell sizes keep it from collapsing.
+	banded := func(c color.RGBA) *image.RGBA {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		for y := 2; y < 4; y++ {
+			for x := range 4 {
+				img.Set(x, y, c)
+			}
+		}
+		return img
+	}
+	band := `<td colspan="4" rowspan="2" style="width:8em;height:2em"></td>`
+
+	var buf bytes.Buffer
+	c := New(WithTargetWidth(4), WithCellSize(2, 1), WithResponsive(true, 0, 0))
+	require.NoError(t, c.Convert(context.Background(), banded(color.RGBA{R: 255, A: 255}), &buf))
+	assert.Contains(t, buf.String(), band)
+
+	buf.Reset()
+	a := &Animation{Frames: []image.Image{banded(color.RGBA{R: 255, A: 255}), banded(color.RGBA{G: 255, A: 255})}}
+	require.NoError(t, c.ConvertAnimation(context.Background(), a, &buf))
+	assert.Equal(t, 2, strings.Count(buf.String(), band))
+
+	// Fixed-size output keeps transparent cells bare.
+	buf.Reset()
+	require.NoError(t, New(WithTargetWidth(4)).Convert(context.Background(), banded(color.RGBA{R: 255, A: 255}), &buf))
+	assert.Contains(t, buf.String(), `<td colspan="4" rowspan="2"></td>`)
+}
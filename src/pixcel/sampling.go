@@ -0,0 +1,187 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"cmp"
+	"image"
+	"math"
+	"slices"
+	"time"
+)
+
+// FrameSampling selects how animations with more frames than allowed by
+// [WithMaxFrames] are reduced to fit the budget.
+type FrameSampling int
+
+const (
+	// SampleUniform keeps evenly spaced frames, including the first and last,
+	// each with its own delay. Dropped frames shorten the animation, so
+	// playback speeds up. This is the default.
+	SampleUniform FrameSampling = iota
+
+	// SampleDuration keeps the same frames as [SampleUniform], but each kept
+	// frame also takes over the delays of the dropped frames that follow it,
+	// so the total duration is preserved.
+	SampleDuration
+
+	// SampleDistinct keeps the first and last frames plus the frames that
+	// differ most from their predecessor, measured by pixel difference, and
+	// redistributes delays like [SampleDuration]. It suits animations that
+	// hold still for a while and then change quickly.
+	SampleDistinct
+
+	// SampleFPS resamples the animation at the fixed frame rate set by
+	// [WithSampleFPS], showing whichever frame is visible at each tick. The
+	// rate is lowered if needed to stay within the budget. The total duration
+	// is preserved.
+	SampleFPS
+)
+
+// defaultSampleFPS is the frame rate used by [SampleFPS] unless overridden.
+const defaultSampleFPS = 10
+
+// sampleFrames reduces the animation to fit within the maxFrames budget
+// using the configured [FrameSampling] strategy. The returned animation is a
+// shallow copy with only the sampled frames and delays, so keyframe timing
+// stays correct.
+func (c *Converter) sampleFrames(a *Animation) *Animation {
+	n := len(a.Frames)
+	if c.maxFrames <= 0 || n <= c.maxFrames {
+		return a
+	}
+
+	switch c.sampling {
+	case SampleDuration:
+		return pickFrames(a, uniformIndices(n, c.maxFrames), true)
+	case SampleDistinct:
+		return pickFrames(a, distinctIndices(a.Frames, c.maxFrames), true)
+	case SampleFPS:
+		return resampleFPS(a, c.sampleFPS, c.maxFrames)
+	default:
+		return pickFrames(a, uniformIndices(n, c.maxFrames), false)
+	}
+}
+
+// uniformIndices returns m evenly spaced indices out of n, always including
+// the first and last.
+func uniformIndices(n, m int) []int {
+	indices := make([]int, 0, m)
+	indices = append(indices, 0)
+	for i := 1; i < m-1; i++ {
+		indices = append(indices, i*(n-1)/(m-1))
+	}
+	return append(indices, n-1)
+}
+
+// distinctIndices returns the first and last index plus the m-2 indices of
+// the frames that differ most from the frame before them, in order.
+func distinctIndices(frames []image.Image, m int) []int {
+	n := len(frames)
+	middle := make([]int, 0, n-2)
+	scores := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		middle = append(middle, i)
+		scores[i] = frameDiff(frames[i-1], frames[i])
+	}
+
+	// Highest score first; ties keep the earlier frame.
+	slices.SortStableFunc(middle, func(a, b int) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+	middle = middle[:max(m-2, 0)]
+	slices.Sort(middle)
+
+	indices := make([]int, 0, m)
+	indices = append(indices, 0)
+	indices = append(indices, middle...)
+	return append(indices, n-1)
+}
+
+// frameDiff returns the mean per-channel difference between two frames,
+// compared pixel by pixel from their top-left corners. Frames of different
+// sizes are maximally different.
+func frameDiff(a, b image.Image) float64 {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Size() != bb.Size() || ab.Empty() {
+		return math.MaxFloat64
+	}
+
+	var sum float64
+	for y := range ab.Dy() {
+		for x := range ab.Dx() {
+			r1, g1, b1, a1 := colorAt(a, ab.Min.X+x, ab.Min.Y+y)
+			r2, g2, b2, a2 := colorAt(b, bb.Min.X+x, bb.Min.Y+y)
+			sum += absDiff(r1, r2) + absDiff(g1, g2) + absDiff(b1, b2) + absDiff(a1, a2)
+		}
+	}
+	return sum / float64(ab.Dx()*ab.Dy()*4)
+}
+
+// absDiff returns |a-b| as a float.
+func absDiff(a, b uint8) float64 {
+	return math.Abs(float64(a) - float64(b))
+}
+
+// pickFrames returns a copy of a holding only the frames at indices. With
+// keepDuration set, each kept frame also takes over the delays of the frames
+// dropped after it; otherwise it keeps only its own delay.
+func pickFrames(a *Animation, indices []int, keepDuration bool) *Animation {
+	sampled := *a
+	sampled.Frames = make([]image.Image, len(indices))
+	sampled.Delays = make([]time.Duration, len(indices))
+	for i, idx := range indices {
+		sampled.Frames[i] = a.Frames[idx]
+		switch {
+		case keepDuration && i+1 < len(indices):
+			for j := idx; j < indices[i+1]; j++ {
+				sampled.Delays[i] += frameDuration(a.Delays, j)
+			}
+		case keepDuration:
+			sampled.Delays[i] = frameDuration(a.Delays, idx)
+		case idx < len(a.Delays):
+			sampled.Delays[i] = a.Delays[idx]
+		}
+	}
+
+	return &sampled
+}
+
+// resampleFPS samples the animation at evenly spaced ticks close to fps per
+// second, lowering the rate so that at most m frames are produced. The total
+// duration is unchanged.
+func resampleFPS(a *Animation, fps float64, m int) *Animation {
+	var total time.Duration
+	for i := range a.Frames {
+		total += frameDuration(a.Delays, i)
+	}
+
+	// Number of ticks, rounded so the last frame is not left with a sliver.
+	count := min(int(math.Round(total.Seconds()*fps)), m)
+	count = max(count, 1)
+	step := total / time.Duration(count)
+
+	sampled := *a
+	sampled.Frames = make([]image.Image, count)
+	sampled.Delays = make([]time.Duration, count)
+
+	frame := 0
+	end := frameDuration(a.Delays, 0) // end time of the current frame
+	for i := range count {
+		t := time.Duration(i) * step
+		for t >= end && frame < len(a.Frames)-1 {
+			frame++
+			end += frameDuration(a.Delays, frame)
+		}
+		sampled.Frames[i] = a.Frames[frame]
+		sampled.Delays[i] = step
+	}
+
+	// Give the rounding remainder to the last sample.
+	sampled.Delays[count-1] += total - step*time.Duration(count)
+
+	return &sampled
+}
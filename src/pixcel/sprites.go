@@ -0,0 +1,253 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"image"
+	"io"
+	"time"
+)
+
+// Sprite is a single named tile sliced from a spritesheet.
+type Sprite struct {
+	// Name identifies the sprite. It is used as the anchor id in the page
+	// produced by [Converter.ConvertSprites] and by the CLI as the file name.
+	Name string
+
+	// Image holds the sprite pixels. Its bounds are the tile's position
+	// within the original sheet.
+	Image image.Image
+}
+
+// spriteData holds the rendered rows for a single sprite on the sprites page.
+type spriteData struct {
+	Name   string
+	Width  int
+	Height int
+	Rows   [][]Cell
+}
+
+// spritesTemplateData holds all data injected into the sprites page template.
+type spritesTemplateData struct {
+	WithHTML   bool
+	Title      string
+	Sprites    []spriteData
+	CellWidth  int
+	CellHeight int
+	SmoothLoad bool
+	Obfuscate  bool
+	Theme      Theme
+	Prefix     string
+}
+
+// SliceGrid slices a spritesheet into tiles of tileW×tileH pixels, in reading
+// order. Partial tiles along the right and bottom edges and fully transparent
+// tiles are skipped. Sprites are named "sprite-<row>-<col>" after their grid
+// position.
+//
+// SliceGrid returns [ErrNilImage] if sheet is nil, [ErrInvalidTileSize] if a
+// tile dimension is not positive, and [ErrNoSprites] if no tile has any
+// visible pixel.
+func SliceGrid(sheet image.Image, tileW, tileH int) ([]Sprite, error) {
+	if sheet == nil {
+		return nil, ErrNilImage
+	}
+	if tileW <= 0 || tileH <= 0 {
+		return nil, ErrInvalidTileSize
+	}
+
+	b := sheet.Bounds()
+	var sprites []Sprite
+	for row := 0; (row+1)*tileH <= b.Dy(); row++ {
+		for col := 0; (col+1)*tileW <= b.Dx(); col++ {
+			r := image.Rect(col*tileW, row*tileH, (col+1)*tileW, (row+1)*tileH).Add(b.Min)
+			if isTransparent(sheet, r) {
+				continue
+			}
+			sprites = append(sprites, Sprite{
+				Name:  fmt.Sprintf("sprite-%d-%d", row, col),
+				Image: subImage(sheet, r),
+			})
+		}
+	}
+
+	if len(sprites) == 0 {
+		return nil, ErrNoSprites
+	}
+	return sprites, nil
+}
+
+// SliceAuto slices a spritesheet by detecting the fully transparent gutters
+// between tiles. The sheet is first split into bands at transparent rows,
+// then each band is split at transparent columns, and each tile is trimmed
+// to the rows holding visible pixels, so sprites shorter than their band
+// keep their own height. Sprites are named "sprite-<band>-<col>" in reading
+// order.
+//
+// SliceAuto returns [ErrNilImage] if sheet is nil and [ErrNoSprites] if the
+// sheet has no visible pixels.
+func SliceAuto(sheet image.Image) ([]Sprite, error) {
+	if sheet == nil {
+		return nil, ErrNilImage
+	}
+
+	b := sheet.Bounds()
+	bands := visibleRuns(b.Dy(), func(y int) bool {
+		return !isTransparent(sheet, image.Rect(b.Min.X, b.Min.Y+y, b.Max.X, b.Min.Y+y+1))
+	})
+
+	var sprites []Sprite
+	for bi, band := range bands {
+		cols := visibleRuns(b.Dx(), func(x int) bool {
+			return !isTransparent(sheet, image.Rect(b.Min.X+x, b.Min.Y+band[0], b.Min.X+x+1, b.Min.Y+band[1]))
+		})
+		for ci, col := range cols {
+			r := trimRows(sheet, image.Rect(col[0], band[0], col[1], band[1]).Add(b.Min))
+			sprites = append(sprites, Sprite{
+				Name:  fmt.Sprintf("sprite-%d-%d", bi, ci),
+				Image: subImage(sheet, r),
+			})
+		}
+	}
+
+	if len(sprites) == 0 {
+		return nil, ErrNoSprites
+	}
+	return sprites, nil
+}
+
+// trimRows shrinks r to the rows between the first and last row of r that
+// have a visible pixel. r must contain at least one visible pixel.
+func trimRows(img image.Image, r image.Rectangle) image.Rectangle {
+	row := func(y int) image.Rectangle { return image.Rect(r.Min.X, y, r.Max.X, y+1) }
+	for r.Min.Y < r.Max.Y-1 && isTransparent(img, row(r.Min.Y)) {
+		r.Min.Y++
+	}
+	for r.Max.Y-1 > r.Min.Y && isTransparent(img, row(r.Max.Y-1)) {
+		r.Max.Y--
+	}
+	return r
+}
+
+// visibleRuns returns the [start, end) ranges of consecutive positions in
+// [0, n) for which visible reports true.
+func visibleRuns(n int, visible func(int) bool) [][2]int {
+	var runs [][2]int
+	start := -1
+	for i := range n {
+		v := visible(i)
+		switch {
+		case v && start < 0:
+			start = i
+		case !v && start >= 0:
+			runs = append(runs, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, [2]int{start, n})
+	}
+	return runs
+}
+
+// isTransparent reports whether every pixel of img inside r is fully transparent.
+func isTransparent(img image.Image, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ConvertSprites converts each sprite and writes them to a single HTML page,
+// one table per sprite, each wrapped in a section whose id is the sprite name
+// so individual sprites can be linked with named anchors (e.g. #sprite-0-1).
+// Every sprite is scaled independently using the converter's options. With
+// [ProfileEmail] each sprite is an email-safe table labelled with its name.
+//
+// ConvertSprites returns [ErrNoSprites] if sprites is empty, [ErrNilWriter]
+// if w is nil, and [ErrNilImage] if any sprite has a nil image.
+func (c *Converter) ConvertSprites(ctx context.Context, sprites []Sprite, w io.Writer) error {
+	if len(sprites) == 0 {
+		return ErrNoSprites
+	}
+	if w == nil {
+		return ErrNilWriter
+	}
+	if c.profile == ProfileEmail {
+		return c.generateEmailSprites(ctx, sprites, w)
+	}
+
+	data := &spritesTemplateData{
+		WithHTML:   c.withHTML,
+		Title:      html.EscapeString(c.htmlTitle),
+		Sprites:    make([]spriteData, 0, len(sprites)),
+		CellWidth:  c.cellWidth,
+		CellHeight: c.cellHeight,
+		SmoothLoad: c.smoothLoad,
+		Obfuscate:  c.obfuscate,
+		Theme:      c.theme,
+		Prefix:     c.classPrefix(),
+	}
+
+	for _, s := range sprites {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if s.Image == nil {
+			return ErrNilImage
+		}
+
+		scaled, err := c.scaleImage(s.Image)
+		if err != nil {
+			return err
+		}
+		rows, err := c.buildRows(ctx, scaled)
+		if err != nil {
+			return err
+		}
+
+		data.Sprites = append(data.Sprites, spriteData{
+			Name:   html.EscapeString(s.Name),
+			Width:  scaled.Bounds().Dx(),
+			Height: scaled.Bounds().Dy(),
+			Rows:   rows,
+		})
+	}
+
+	return c.render(w, spritesTmpl, data)
+}
+
+// ConvertSpriteAnimation assembles the sprites, in order, into a single
+// CSS-animated output, showing each sprite for the given delay. It renders
+// the sprites as an [Animation] via [Converter.ConvertAnimation], including
+// frame sampling via [WithMaxFrames]. All frames are scaled to the target
+// size derived from the first sprite.
+//
+// ConvertSpriteAnimation returns [ErrNoSprites] if sprites is empty,
+// [ErrNilWriter] if w is nil, and [ErrNilImage] if any sprite has a nil image.
+func (c *Converter) ConvertSpriteAnimation(ctx context.Context, sprites []Sprite, delay time.Duration, w io.Writer) error {
+	if len(sprites) == 0 {
+		return ErrNoSprites
+	}
+
+	a := &Animation{
+		Frames: make([]image.Image, len(sprites)),
+		Delays: make([]time.Duration, len(sprites)),
+	}
+	for i, s := range sprites {
+		a.Frames[i] = s.Image
+		a.Delays[i] = delay
+	}
+
+	return c.ConvertAnimation(ctx, a, w)
+}
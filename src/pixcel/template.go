@@ -0,0 +1,195 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"text/template"
+)
+
+// Names of the templates a custom template set provides (see [WithTemplate]).
+const (
+	// TemplateImage renders still images with [TemplateData].
+	TemplateImage = "image"
+	// TemplateAnimation renders animations with [AnimationTemplateData].
+	TemplateAnimation = "animation"
+)
+
+// TemplateData is the data passed to the template that renders a still image.
+// It is part of the stable contract for custom templates: fields are only
+// ever added, never removed or changed in meaning.
+type TemplateData struct {
+	WithHTML     bool     // full HTML document requested (see [WithHTMLWrapper])
+	WebComponent bool     // wrap the art in a <pixcel-art> element (see [WithWebComponent])
+	Title        string   // page title, already HTML-escaped
+	Width        int      // width in cells
+	Height       int      // height in cells
+	Rows         [][]Cell // meshed table rows; a row may be empty when covered by rowspans
+	CellWidth    int      // CSS pixel width of one cell
+	CellHeight   int      // CSS pixel height of one cell
+	SmoothLoad   bool     // hide the content until the page has loaded
+	Obfuscate    bool     // colours in Rows use randomised CSS notations
+	Theme        Theme    // page styling (see [WithTheme])
+	Prefix       string   // class and keyframe name prefix (see [WithClassPrefix])
+	Responsive   bool     // scale to the container width (see [WithResponsive])
+	MinWidth     int      // responsive minimum width in CSS pixels, 0 for none
+	MaxWidth     int      // responsive maximum width in CSS pixels, 0 for none
+	Unit         string   // CSS unit of cell sizes: "px", or "em" when responsive
+	AltText      string   // text alternative, already HTML-escaped (see [WithAltText])
+	Description  string   // longer description shown as a caption, already HTML-escaped
+}
+
+// AnimationTemplateData is the data passed to the template that renders an
+// animation. Like [TemplateData], it only ever gains fields.
+type AnimationTemplateData struct {
+	WithHTML         bool
+	WebComponent     bool   // wrap the art in a <pixcel-art> element
+	Title            string // already HTML-escaped
+	Width            int    // width in cells
+	Height           int    // height in cells
+	TotalDurationCSS string // duration of one play as a CSS time, e.g. "1.200s"
+	LoopCount        int    // number of plays; 0 loops forever
+	Frames           []AnimationFrame
+	Player           []PlayerFrame // non-nil enables the scripted player
+	ReducedMotion    bool          // show a poster frame for prefers-reduced-motion
+	Poster           int           // poster frame index in playback order
+	PosterLayer      int           // index in Frames of the layer holding the poster frame
+	PosterBase       bool          // poster is a delta frame drawn over the base layer
+	CellWidth        int
+	CellHeight       int
+	SmoothLoad       bool
+	Obfuscate        bool
+	Theme            Theme
+	Prefix           string
+	Responsive       bool
+	MinWidth         int
+	MaxWidth         int
+	Unit             string
+	AltText          string // already HTML-escaped
+	Description      string // already HTML-escaped
+}
+
+// AnimationFrame is one stacked layer of an animation. A layer shows one or
+// more frames of the animation, as described by its Keyframes. Delta-encoded
+// layers (see [WithFrameDelta]) carry Patches drawn over the first layer
+// instead of Rows.
+type AnimationFrame struct {
+	Rows      [][]Cell
+	Patches   []Patch
+	Delta     bool
+	Keyframes []Keyframe
+}
+
+// Keyframe represents a single step in the CSS @keyframes rule of a layer.
+type Keyframe struct {
+	Percent string // CSS keyframe selector, e.g. "25.0000%"
+	Opacity int    // 0 or 1
+}
+
+// Patch is a solid rectangle of changed pixels, positioned absolutely over
+// the first layer in delta-encoded animations.
+type Patch struct {
+	X, Y  int    // top-left corner in cells
+	W, H  int    // size in cells
+	Color string // CSS background-color declaration
+}
+
+// PlayerFrame is one step of the player timeline: the layer to show, whether
+// the base layer shows underneath it (delta frames), and the delay in ms.
+type PlayerFrame struct {
+	Layer int
+	Base  bool
+	Delay int64
+}
+
+// defaultPrefix is the class and keyframe name prefix unless overridden.
+const defaultPrefix = "pixcel"
+
+// validPrefix reports whether s is a CSS identifier that can prefix class and
+// keyframe names as is.
+func validPrefix(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// classPrefix returns the prefix for one conversion, with a random suffix
+// when [WithUniquePrefix] is enabled.
+func (c *Converter) classPrefix() string {
+	if !c.uniquePrefix {
+		return c.prefix
+	}
+	return fmt.Sprintf("%s-%06x", c.prefix, randIntn(1<<24))
+}
+
+// sizeUnit returns the CSS unit of cell sizes in the output.
+func (c *Converter) sizeUnit() string {
+	if c.responsive {
+		return "em"
+	}
+	return "px"
+}
+
+// TemplateFuncs returns the helper functions used by the built-in templates:
+// inc and dec add and subtract one, and mul multiplies two integers. Custom
+// templates passed to [WithTemplate] must be parsed with these functions to
+// use them.
+func TemplateFuncs() template.FuncMap {
+	return maps.Clone(templateFuncs)
+}
+
+// checkTemplate reports an error if t defines neither of the templates a
+// custom template set must provide.
+func checkTemplate(t *template.Template) error {
+	if t.Lookup(TemplateImage) == nil && t.Lookup(TemplateAnimation) == nil {
+		return fmt.Errorf("%w: defines neither %q nor %q", ErrInvalidTemplate, TemplateImage, TemplateAnimation)
+	}
+	return nil
+}
+
+// execute renders data with the named template of the custom template set,
+// or with the built-in template def when none is configured.
+func (c *Converter) execute(w io.Writer, def *template.Template, name string, data any) error {
+	if c.pageTmplErr != nil {
+		return c.pageTmplErr
+	}
+	if c.pageTmpl == nil {
+		return c.render(w, def, data)
+	}
+
+	t := c.pageTmpl.Lookup(name)
+	if t == nil {
+		return fmt.Errorf("%w: missing %q template", ErrInvalidTemplate, name)
+	}
+	return c.render(w, t, data)
+}
+
+// render executes t with data, minifying the output when [WithMinify] is
+// enabled.
+func (c *Converter) render(w io.Writer, t *template.Template, data any) error {
+	if !c.minify {
+		return t.Execute(w, data)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return err
+	}
+	_, err := w.Write(minifyHTML(buf.Bytes()))
+	return err
+}
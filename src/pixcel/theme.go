@@ -0,0 +1,71 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import "strings"
+
+// Theme controls the look of the page wrapped around the art when
+// [WithHTMLWrapper] is enabled. Colours and the shadow are CSS values; an
+// empty colour leaves the corresponding property unset, and an empty shadow
+// keeps the built-in card shadow. Start from one of the presets and adjust
+// it to match a site's branding.
+type Theme struct {
+	Background string // page background, e.g. "#1a1a2e" or "transparent"
+	Surface    string // background of the card holding the art
+	Text       string // text colour of labels and player controls
+	Padding    int    // card padding in CSS pixels
+	Radius     int    // card corner radius in CSS pixels
+	Shadow     string // card box-shadow, e.g. "none"; empty for the built-in one
+	Centered   bool   // centre the card in the viewport instead of top-left
+}
+
+// Theme presets for [WithTheme].
+var (
+	// ThemeDark is the default: a dark page with a raised, centred card.
+	ThemeDark = Theme{
+		Background: "#1a1a2e",
+		Surface:    "#16213e",
+		Text:       "#e0e0e0",
+		Padding:    24,
+		Radius:     12,
+		Centered:   true,
+	}
+
+	// ThemeLight is a light page with a subtle, centred card.
+	ThemeLight = Theme{
+		Background: "#f5f5f7",
+		Surface:    "#ffffff",
+		Text:       "#1a1a2e",
+		Padding:    24,
+		Radius:     12,
+		Shadow:     "0 4px 16px rgba(0,0,0,0.08), 0 0 0 1px rgba(0,0,0,0.06)",
+		Centered:   true,
+	}
+
+	// ThemeTransparent draws only the art in the top-left corner, for
+	// embedding the page in an iframe over existing content.
+	ThemeTransparent = Theme{
+		Background: "transparent",
+		Surface:    "transparent",
+		Text:       "inherit",
+		Shadow:     "none",
+	}
+)
+
+// valid reports whether the theme can be embedded in a style sheet safely:
+// sizes are non-negative and CSS values cannot end the declaration, the rule
+// or the style element.
+func (t Theme) valid() bool {
+	if t.Padding < 0 || t.Radius < 0 {
+		return false
+	}
+	for _, v := range []string{t.Background, t.Surface, t.Text, t.Shadow} {
+		if strings.ContainsAny(v, ";{}<>\\\n\r") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2026 H0llyW00dzZ All rights reserved.
+//
+// By accessing or using this software, you agree to be bound by the terms
+// of the License Agreement, which you can find at LICENSE files.
+
+package pixcel
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"io"
+	"time"
+
+	"golang.org/x/image/riff"
+	"golang.org/x/image/webp"
+)
+
+// WebP RIFF chunk identifiers.
+var (
+	fccWEBP = riff.FourCC{'W', 'E', 'B', 'P'}
+	fccVP8X = riff.FourCC{'V', 'P', '8', 'X'}
+	fccANIM = riff.FourCC{'A', 'N', 'I', 'M'}
+	fccANMF = riff.FourCC{'A', 'N', 'M', 'F'}
+	fccALPH = riff.FourCC{'A', 'L', 'P', 'H'}
+	fccVP8  = riff.FourCC{'V', 'P', '8', ' '}
+	fccVP8L = riff.FourCC{'V', 'P', '8', 'L'}
+)
+
+const (
+	// webpAnimationFlag is the VP8X flag bit marking an animated file.
+	webpAnimationFlag = 1 << 1
+	// webpAlphaFlag is the VP8X flag bit marking a file with an ALPH chunk.
+	webpAlphaFlag = 1 << 4
+)
+
+// webpAnimation is the decoded content of an animated WebP file.
+type webpAnimation struct {
+	Width, Height int
+	LoopCount     int // 0 loops forever
+	Frames        []rasterFrame
+}
+
+// ConvertWebP decodes a WebP image from r and writes HTML pixel art to w.
+// Animated WebP files are composited frame by frame (honouring the blend and
+// dispose flags) and rendered with the same CSS keyframe animation as
+// [Converter.ConvertAnimation], including frame sampling via [WithMaxFrames].
+// The loop count stored in the file is honoured. Still WebP files are
+// rendered like [Converter.Convert].
+//
+// ConvertWebP returns [ErrNilReader] if r is nil, [ErrNilWriter] if w is nil,
+// [ErrInvalidWebP] if the data is not a valid WebP file, and [ErrNoFrames] if
+// an animated file contains no frames.
+func (c *Converter) ConvertWebP(ctx context.Context, r io.Reader, w io.Writer) error {
+	if r == nil {
+		return ErrNilReader
+	}
+	if w == nil {
+		return ErrNilWriter
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	anim, err := decodeWebPAnimation(data)
+	if err != nil {
+		return err
+	}
+	if anim == nil {
+		img, err := webp.Decode(bytes.NewReader(data))
+		if err != nil {
+			return ErrInvalidWebP
+		}
+		return c.Convert(ctx, img, w)
+	}
+	if len(anim.Frames) == 0 {
+		return ErrNoFrames
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	composited, err := c.compositeRasterFrames(anim.Width, anim.Height, anim.Frames)
+	if err != nil {
+		return err
+	}
+
+	return c.renderAnimation(ctx, &Animation{
+		Frames:    rgbaFrames(composited),
+		Delays:    rasterDelays(anim.Frames),
+		LoopCount: anim.LoopCount,
+	}, w)
+}
+
+// decodeWebPAnimation parses the RIFF container of a WebP file. It returns a
+// nil animation and no error for still images, which the standard decoder
+// handles directly.
+func decodeWebPAnimation(data []byte) (*webpAnimation, error) {
+	formType, rr, err := riff.NewReader(bytes.NewReader(data))
+	if err != nil || formType != fccWEBP {
+		return nil, ErrInvalidWebP
+	}
+
+	var anim *webpAnimation
+	for {
+		id, n, chunk, err := rr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidWebP
+		}
+		body, err := io.ReadAll(chunk)
+		if err != nil || uint32(len(body)) != n {
+			return nil, ErrInvalidWebP
+		}
+
+		switch id {
+		case fccVP8X:
+			if len(body) < 10 {
+				return nil, ErrInvalidWebP
+			}
+			if body[0]&webpAnimationFlag == 0 {
+				return nil, nil
+			}
+			anim = &webpAnimation{
+				Width:  int(uint24(body[4:])) + 1,
+				Height: int(uint24(body[7:])) + 1,
+			}
+			if anim.Width > maxCanvasPixels/anim.Height {
+				return nil, ErrInvalidWebP
+			}
+		case fccANIM:
+			if anim == nil || len(body) < 6 {
+				return nil, ErrInvalidWebP
+			}
+			anim.LoopCount = int(binary.LittleEndian.Uint16(body[4:6]))
+		case fccANMF:
+			if anim == nil || len(anim.Frames) >= maxAnimationPixels/(anim.Width*anim.Height) {
+				return nil, ErrInvalidWebP
+			}
+			frame, err := decodeWebPFrame(body)
+			if err != nil {
+				return nil, err
+			}
+			if !frame.Bounds.In(image.Rect(0, 0, anim.Width, anim.Height)) {
+				return nil, ErrInvalidWebP
+			}
+			anim.Frames = append(anim.Frames, frame)
+		case fccVP8, fccVP8L:
+			// A bitstream chunk at the top level is a still image.
+			if anim == nil {
+				return nil, nil
+			}
+		}
+	}
+
+	if anim == nil {
+		return nil, ErrInvalidWebP
+	}
+	return anim, nil
+}
+
+// decodeWebPFrame decodes the payload of an ANMF chunk. The frame bitstream
+// (ALPH + VP8, or VP8L) is rewrapped as a standalone WebP file so the
+// standard decoder can handle it.
+func decodeWebPFrame(body []byte) (rasterFrame, error) {
+	if len(body) < 16 {
+		return rasterFrame{}, ErrInvalidWebP
+	}
+
+	x := int(uint24(body[0:])) * 2
+	y := int(uint24(body[3:])) * 2
+	w := int(uint24(body[6:])) + 1
+	h := int(uint24(body[9:])) + 1
+	flags := body[15]
+
+	frame := rasterFrame{
+		Bounds: image.Rect(x, y, x+w, y+h),
+		Delay:  time.Duration(uint24(body[12:])) * time.Millisecond,
+		Blend:  flags&0x02 == 0,
+	}
+	if flags&0x01 != 0 {
+		frame.Dispose = disposeBackground
+	}
+
+	payload := body[16:]
+	var vp8x [10]byte
+	if bytes.HasPrefix(payload, fccALPH[:]) {
+		vp8x[0] = webpAlphaFlag
+	}
+	putUint24(vp8x[4:], uint32(w-1))
+	putUint24(vp8x[7:], uint32(h-1))
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(4+8+len(vp8x)+len(payload)))
+	buf.WriteString("WEBP")
+	buf.WriteString("VP8X")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(vp8x)))
+	buf.Write(vp8x[:])
+	buf.Write(payload)
+
+	img, err := webp.Decode(&buf)
+	if err != nil {
+		return rasterFrame{}, ErrInvalidWebP
+	}
+	frame.Image = img
+	return frame, nil
+}
+
+// uint24 reads a little-endian 24-bit unsigned integer.
+func uint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+// putUint24 writes v as a little-endian 24-bit unsigned integer.
+func putUint24(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}